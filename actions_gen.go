@@ -0,0 +1,929 @@
+// Code generated by internal/gen; DO NOT EDIT.
+
+package ghactions
+
+import "github.com/google/go-github/v71/github"
+
+// handlers the event handlers of an Action.
+type handlers struct {
+	onBranchProtectionConfiguration func(*github.Client, *github.BranchProtectionConfigurationEvent) error
+	onBranchProtectionRule          func(*github.Client, *github.BranchProtectionRuleEvent) error
+	onCheckRun                      func(*github.Client, *github.CheckRunEvent) error
+	onCheckSuite                    func(*github.Client, *github.CheckSuiteEvent) error
+	onClassicProject                func(*github.Client, *ClassicProjectEvent) error
+	onClassicProjectCard            func(*github.Client, *ClassicProjectCardEvent) error
+	onClassicProjectColumn          func(*github.Client, *ClassicProjectColumnEvent) error
+	onCodeScanningAlert             func(*github.Client, *github.CodeScanningAlertEvent) error
+	onCommitComment                 func(*github.Client, *github.CommitCommentEvent) error
+	onContentReference              func(*github.Client, *github.ContentReferenceEvent) error
+	onCreate                        func(*github.Client, *github.CreateEvent) error
+	onCustomProperty                func(*github.Client, *github.CustomPropertyEvent) error
+	onCustomPropertyValues          func(*github.Client, *github.CustomPropertyValuesEvent) error
+	onDelete                        func(*github.Client, *github.DeleteEvent) error
+	onDependabotAlert               func(*github.Client, *github.DependabotAlertEvent) error
+	onDeployKey                     func(*github.Client, *github.DeployKeyEvent) error
+	onDeployment                    func(*github.Client, *github.DeploymentEvent) error
+	onDeploymentProtectionRule      func(*github.Client, *github.DeploymentProtectionRuleEvent) error
+	onDeploymentReview              func(*github.Client, *github.DeploymentReviewEvent) error
+	onDeploymentStatus              func(*github.Client, *github.DeploymentStatusEvent) error
+	onDiscussion                    func(*github.Client, *github.DiscussionEvent) error
+	onDiscussionComment             func(*github.Client, *github.DiscussionCommentEvent) error
+	onFork                          func(*github.Client, *github.ForkEvent) error
+	onGitHubAppAuthorization        func(*github.Client, *github.GitHubAppAuthorizationEvent) error
+	onGollum                        func(*github.Client, *github.GollumEvent) error
+	onInstallation                  func(*github.Client, *github.InstallationEvent) error
+	onInstallationRepositories      func(*github.Client, *github.InstallationRepositoriesEvent) error
+	onInstallationTarget            func(*github.Client, *github.InstallationTargetEvent) error
+	onIssueComment                  func(*github.Client, *github.IssueCommentEvent) error
+	onIssues                        func(*github.Client, *github.IssuesEvent) error
+	onLabel                         func(*github.Client, *github.LabelEvent) error
+	onMarketplacePurchase           func(*github.Client, *github.MarketplacePurchaseEvent) error
+	onMember                        func(*github.Client, *github.MemberEvent) error
+	onMembership                    func(*github.Client, *github.MembershipEvent) error
+	onMergeGroup                    func(*github.Client, *github.MergeGroupEvent) error
+	onMeta                          func(*github.Client, *github.MetaEvent) error
+	onMilestone                     func(*github.Client, *github.MilestoneEvent) error
+	onOrgBlock                      func(*github.Client, *github.OrgBlockEvent) error
+	onOrganization                  func(*github.Client, *github.OrganizationEvent) error
+	onPackage                       func(*github.Client, *github.PackageEvent) error
+	onPageBuild                     func(*github.Client, *github.PageBuildEvent) error
+	onPersonalAccessTokenRequest    func(*github.Client, *github.PersonalAccessTokenRequestEvent) error
+	onPing                          func(*github.Client, *github.PingEvent) error
+	onProject                       func(*github.Client, *github.ProjectV2Event) error
+	onProjectItem                   func(*github.Client, *github.ProjectV2ItemEvent) error
+	onPublic                        func(*github.Client, *github.PublicEvent) error
+	onPullRequest                   func(*github.Client, *github.PullRequestEvent) error
+	onPullRequestReview             func(*github.Client, *github.PullRequestReviewEvent) error
+	onPullRequestReviewComment      func(*github.Client, *github.PullRequestReviewCommentEvent) error
+	onPullRequestReviewThread       func(*github.Client, *github.PullRequestReviewThreadEvent) error
+	onPullRequestTarget             func(*github.Client, *github.PullRequestTargetEvent) error
+	onPush                          func(*github.Client, *github.PushEvent) error
+	onRegistryPackage               func(*github.Client, *RegistryPackageEvent) error
+	onRelease                       func(*github.Client, *github.ReleaseEvent) error
+	onRepository                    func(*github.Client, *github.RepositoryEvent) error
+	onRepositoryAdvisory            func(*github.Client, *RepositoryAdvisoryEvent) error
+	onRepositoryDispatch            func(*github.Client, *github.RepositoryDispatchEvent) error
+	onRepositoryImport              func(*github.Client, *github.RepositoryImportEvent) error
+	onRepositoryRuleset             func(*github.Client, *github.RepositoryRulesetEvent) error
+	onRepositoryVulnerabilityAlert  func(*github.Client, *github.RepositoryVulnerabilityAlertEvent) error
+	onSchedule                      func(*github.Client, *ScheduleEvent) error
+	onSecretScanningAlert           func(*github.Client, *github.SecretScanningAlertEvent) error
+	onSecretScanningAlertLocation   func(*github.Client, *github.SecretScanningAlertLocationEvent) error
+	onSecurityAdvisory              func(*github.Client, *github.SecurityAdvisoryEvent) error
+	onSecurityAndAnalysis           func(*github.Client, *github.SecurityAndAnalysisEvent) error
+	onSponsorship                   func(*github.Client, *github.SponsorshipEvent) error
+	onStar                          func(*github.Client, *github.StarEvent) error
+	onStatus                        func(*github.Client, *github.StatusEvent) error
+	onTeam                          func(*github.Client, *github.TeamEvent) error
+	onTeamAdd                       func(*github.Client, *github.TeamAddEvent) error
+	onUser                          func(*github.Client, *github.UserEvent) error
+	onWatch                         func(*github.Client, *github.WatchEvent) error
+	onWorkflowDispatch              func(*github.Client, *github.WorkflowDispatchEvent) error
+	onWorkflowJob                   func(*github.Client, *github.WorkflowJobEvent) error
+	onWorkflowRun                   func(*github.Client, *github.WorkflowRunEvent) error
+}
+
+// OnBranchProtectionConfiguration BranchProtectionConfiguration handler (event: branch_protection_configuration).
+func (a *Action) OnBranchProtectionConfiguration(eventHandler func(*github.Client, *github.BranchProtectionConfigurationEvent) error) *Action {
+	a.onBranchProtectionConfiguration = eventHandler
+	return a
+}
+
+// OnBranchProtectionRule BranchProtectionRule handler (event: branch_protection_rule).
+func (a *Action) OnBranchProtectionRule(eventHandler func(*github.Client, *github.BranchProtectionRuleEvent) error) *Action {
+	a.onBranchProtectionRule = eventHandler
+	return a
+}
+
+// OnCheckRun CheckRun handler (event: check_run).
+func (a *Action) OnCheckRun(eventHandler func(*github.Client, *github.CheckRunEvent) error) *Action {
+	a.onCheckRun = eventHandler
+	return a
+}
+
+// OnCheckSuite CheckSuite handler (event: check_suite).
+func (a *Action) OnCheckSuite(eventHandler func(*github.Client, *github.CheckSuiteEvent) error) *Action {
+	a.onCheckSuite = eventHandler
+	return a
+}
+
+// OnClassicProject ClassicProject handler (event: project).
+func (a *Action) OnClassicProject(eventHandler func(*github.Client, *ClassicProjectEvent) error) *Action {
+	a.onClassicProject = eventHandler
+	return a
+}
+
+// OnClassicProjectCard ClassicProjectCard handler (event: project_card).
+func (a *Action) OnClassicProjectCard(eventHandler func(*github.Client, *ClassicProjectCardEvent) error) *Action {
+	a.onClassicProjectCard = eventHandler
+	return a
+}
+
+// OnClassicProjectColumn ClassicProjectColumn handler (event: project_column).
+func (a *Action) OnClassicProjectColumn(eventHandler func(*github.Client, *ClassicProjectColumnEvent) error) *Action {
+	a.onClassicProjectColumn = eventHandler
+	return a
+}
+
+// OnCodeScanningAlert CodeScanningAlert handler (event: code_scanning_alert).
+func (a *Action) OnCodeScanningAlert(eventHandler func(*github.Client, *github.CodeScanningAlertEvent) error) *Action {
+	a.onCodeScanningAlert = eventHandler
+	return a
+}
+
+// OnCommitComment CommitComment handler (event: commit_comment).
+func (a *Action) OnCommitComment(eventHandler func(*github.Client, *github.CommitCommentEvent) error) *Action {
+	a.onCommitComment = eventHandler
+	return a
+}
+
+// OnContentReference ContentReference handler (event: content_reference).
+func (a *Action) OnContentReference(eventHandler func(*github.Client, *github.ContentReferenceEvent) error) *Action {
+	a.onContentReference = eventHandler
+	return a
+}
+
+// OnCreate Create handler (event: create).
+// The branch and tag filters are optional.
+func (a *Action) OnCreate(eventHandler func(*github.Client, *github.CreateEvent) error, filters ...FilterOption) *Action {
+	a.onCreate = eventHandler
+	a.setFilters("create", filters)
+	return a
+}
+
+// OnCustomProperty CustomProperty handler (event: custom_property).
+func (a *Action) OnCustomProperty(eventHandler func(*github.Client, *github.CustomPropertyEvent) error) *Action {
+	a.onCustomProperty = eventHandler
+	return a
+}
+
+// OnCustomPropertyValues CustomPropertyValues handler (event: custom_property_values).
+func (a *Action) OnCustomPropertyValues(eventHandler func(*github.Client, *github.CustomPropertyValuesEvent) error) *Action {
+	a.onCustomPropertyValues = eventHandler
+	return a
+}
+
+// OnDelete Delete handler (event: delete).
+func (a *Action) OnDelete(eventHandler func(*github.Client, *github.DeleteEvent) error) *Action {
+	a.onDelete = eventHandler
+	return a
+}
+
+// OnDependabotAlert DependabotAlert handler (event: dependabot_alert).
+func (a *Action) OnDependabotAlert(eventHandler func(*github.Client, *github.DependabotAlertEvent) error) *Action {
+	a.onDependabotAlert = eventHandler
+	return a
+}
+
+// OnDeployKey DeployKey handler (event: deploy_key).
+func (a *Action) OnDeployKey(eventHandler func(*github.Client, *github.DeployKeyEvent) error) *Action {
+	a.onDeployKey = eventHandler
+	return a
+}
+
+// OnDeployment Deployment handler (event: deployment).
+func (a *Action) OnDeployment(eventHandler func(*github.Client, *github.DeploymentEvent) error) *Action {
+	a.onDeployment = eventHandler
+	return a
+}
+
+// OnDeploymentProtectionRule DeploymentProtectionRule handler (event: deployment_protection_rule).
+func (a *Action) OnDeploymentProtectionRule(eventHandler func(*github.Client, *github.DeploymentProtectionRuleEvent) error) *Action {
+	a.onDeploymentProtectionRule = eventHandler
+	return a
+}
+
+// OnDeploymentReview DeploymentReview handler (event: deployment_review).
+func (a *Action) OnDeploymentReview(eventHandler func(*github.Client, *github.DeploymentReviewEvent) error) *Action {
+	a.onDeploymentReview = eventHandler
+	return a
+}
+
+// OnDeploymentStatus DeploymentStatus handler (event: deployment_status).
+func (a *Action) OnDeploymentStatus(eventHandler func(*github.Client, *github.DeploymentStatusEvent) error) *Action {
+	a.onDeploymentStatus = eventHandler
+	return a
+}
+
+// OnDiscussion Discussion handler (event: discussion).
+func (a *Action) OnDiscussion(eventHandler func(*github.Client, *github.DiscussionEvent) error) *Action {
+	a.onDiscussion = eventHandler
+	return a
+}
+
+// OnDiscussionComment DiscussionComment handler (event: discussion_comment).
+func (a *Action) OnDiscussionComment(eventHandler func(*github.Client, *github.DiscussionCommentEvent) error) *Action {
+	a.onDiscussionComment = eventHandler
+	return a
+}
+
+// OnFork Fork handler (event: fork).
+func (a *Action) OnFork(eventHandler func(*github.Client, *github.ForkEvent) error) *Action {
+	a.onFork = eventHandler
+	return a
+}
+
+// OnGitHubAppAuthorization GitHubAppAuthorization handler (event: github_app_authorization).
+func (a *Action) OnGitHubAppAuthorization(eventHandler func(*github.Client, *github.GitHubAppAuthorizationEvent) error) *Action {
+	a.onGitHubAppAuthorization = eventHandler
+	return a
+}
+
+// OnGollum Gollum handler (event: gollum).
+func (a *Action) OnGollum(eventHandler func(*github.Client, *github.GollumEvent) error) *Action {
+	a.onGollum = eventHandler
+	return a
+}
+
+// OnInstallation Installation handler (event: installation).
+func (a *Action) OnInstallation(eventHandler func(*github.Client, *github.InstallationEvent) error) *Action {
+	a.onInstallation = eventHandler
+	return a
+}
+
+// OnInstallationRepositories InstallationRepositories handler (event: installation_repositories).
+func (a *Action) OnInstallationRepositories(eventHandler func(*github.Client, *github.InstallationRepositoriesEvent) error) *Action {
+	a.onInstallationRepositories = eventHandler
+	return a
+}
+
+// OnInstallationTarget InstallationTarget handler (event: installation_target).
+func (a *Action) OnInstallationTarget(eventHandler func(*github.Client, *github.InstallationTargetEvent) error) *Action {
+	a.onInstallationTarget = eventHandler
+	return a
+}
+
+// OnIssueComment IssueComment handler (event: issue_comment).
+func (a *Action) OnIssueComment(eventHandler func(*github.Client, *github.IssueCommentEvent) error) *Action {
+	a.onIssueComment = eventHandler
+	return a
+}
+
+// OnIssues Issues handler (event: issues).
+func (a *Action) OnIssues(eventHandler func(*github.Client, *github.IssuesEvent) error) *Action {
+	a.onIssues = eventHandler
+	return a
+}
+
+// OnLabel Label handler (event: label).
+func (a *Action) OnLabel(eventHandler func(*github.Client, *github.LabelEvent) error) *Action {
+	a.onLabel = eventHandler
+	return a
+}
+
+// OnMarketplacePurchase MarketplacePurchase handler (event: marketplace_purchase).
+func (a *Action) OnMarketplacePurchase(eventHandler func(*github.Client, *github.MarketplacePurchaseEvent) error) *Action {
+	a.onMarketplacePurchase = eventHandler
+	return a
+}
+
+// OnMember Member handler (event: member).
+func (a *Action) OnMember(eventHandler func(*github.Client, *github.MemberEvent) error) *Action {
+	a.onMember = eventHandler
+	return a
+}
+
+// OnMembership Membership handler (event: membership).
+func (a *Action) OnMembership(eventHandler func(*github.Client, *github.MembershipEvent) error) *Action {
+	a.onMembership = eventHandler
+	return a
+}
+
+// OnMergeGroup MergeGroup handler (event: merge_group).
+func (a *Action) OnMergeGroup(eventHandler func(*github.Client, *github.MergeGroupEvent) error) *Action {
+	a.onMergeGroup = eventHandler
+	return a
+}
+
+// OnMeta Meta handler (event: meta).
+func (a *Action) OnMeta(eventHandler func(*github.Client, *github.MetaEvent) error) *Action {
+	a.onMeta = eventHandler
+	return a
+}
+
+// OnMilestone Milestone handler (event: milestone).
+func (a *Action) OnMilestone(eventHandler func(*github.Client, *github.MilestoneEvent) error) *Action {
+	a.onMilestone = eventHandler
+	return a
+}
+
+// OnOrgBlock OrgBlock handler (event: org_block).
+func (a *Action) OnOrgBlock(eventHandler func(*github.Client, *github.OrgBlockEvent) error) *Action {
+	a.onOrgBlock = eventHandler
+	return a
+}
+
+// OnOrganization Organization handler (event: organization).
+func (a *Action) OnOrganization(eventHandler func(*github.Client, *github.OrganizationEvent) error) *Action {
+	a.onOrganization = eventHandler
+	return a
+}
+
+// OnPackage Package handler (event: package).
+func (a *Action) OnPackage(eventHandler func(*github.Client, *github.PackageEvent) error) *Action {
+	a.onPackage = eventHandler
+	return a
+}
+
+// OnPageBuild PageBuild handler (event: page_build).
+func (a *Action) OnPageBuild(eventHandler func(*github.Client, *github.PageBuildEvent) error) *Action {
+	a.onPageBuild = eventHandler
+	return a
+}
+
+// OnPersonalAccessTokenRequest PersonalAccessTokenRequest handler (event: personal_access_token_request).
+func (a *Action) OnPersonalAccessTokenRequest(eventHandler func(*github.Client, *github.PersonalAccessTokenRequestEvent) error) *Action {
+	a.onPersonalAccessTokenRequest = eventHandler
+	return a
+}
+
+// OnPing Ping handler (event: ping).
+func (a *Action) OnPing(eventHandler func(*github.Client, *github.PingEvent) error) *Action {
+	a.onPing = eventHandler
+	return a
+}
+
+// OnProject Project handler (event: projects_v2).
+func (a *Action) OnProject(eventHandler func(*github.Client, *github.ProjectV2Event) error) *Action {
+	a.onProject = eventHandler
+	return a
+}
+
+// OnProjectItem ProjectItem handler (event: projects_v2_item).
+func (a *Action) OnProjectItem(eventHandler func(*github.Client, *github.ProjectV2ItemEvent) error) *Action {
+	a.onProjectItem = eventHandler
+	return a
+}
+
+// OnPublic Public handler (event: public).
+func (a *Action) OnPublic(eventHandler func(*github.Client, *github.PublicEvent) error) *Action {
+	a.onPublic = eventHandler
+	return a
+}
+
+// OnPullRequest PullRequest handler (event: pull_request).
+// The branch and tag filters are optional.
+func (a *Action) OnPullRequest(eventHandler func(*github.Client, *github.PullRequestEvent) error, filters ...FilterOption) *Action {
+	a.onPullRequest = eventHandler
+	a.setFilters("pull_request", filters)
+	return a
+}
+
+// OnPullRequestReview PullRequestReview handler (event: pull_request_review).
+func (a *Action) OnPullRequestReview(eventHandler func(*github.Client, *github.PullRequestReviewEvent) error) *Action {
+	a.onPullRequestReview = eventHandler
+	return a
+}
+
+// OnPullRequestReviewComment PullRequestReviewComment handler (event: pull_request_review_comment).
+func (a *Action) OnPullRequestReviewComment(eventHandler func(*github.Client, *github.PullRequestReviewCommentEvent) error) *Action {
+	a.onPullRequestReviewComment = eventHandler
+	return a
+}
+
+// OnPullRequestReviewThread PullRequestReviewThread handler (event: pull_request_review_thread).
+func (a *Action) OnPullRequestReviewThread(eventHandler func(*github.Client, *github.PullRequestReviewThreadEvent) error) *Action {
+	a.onPullRequestReviewThread = eventHandler
+	return a
+}
+
+// OnPullRequestTarget PullRequestTarget handler (event: pull_request_target).
+// The branch and tag filters are optional.
+func (a *Action) OnPullRequestTarget(eventHandler func(*github.Client, *github.PullRequestTargetEvent) error, filters ...FilterOption) *Action {
+	a.onPullRequestTarget = eventHandler
+	a.setFilters("pull_request_target", filters)
+	return a
+}
+
+// OnPush Push handler (event: push).
+// The branch and tag filters are optional.
+func (a *Action) OnPush(eventHandler func(*github.Client, *github.PushEvent) error, filters ...FilterOption) *Action {
+	a.onPush = eventHandler
+	a.setFilters("push", filters)
+	return a
+}
+
+// OnRegistryPackage RegistryPackage handler (event: registry_package).
+func (a *Action) OnRegistryPackage(eventHandler func(*github.Client, *RegistryPackageEvent) error) *Action {
+	a.onRegistryPackage = eventHandler
+	return a
+}
+
+// OnRelease Release handler (event: release).
+func (a *Action) OnRelease(eventHandler func(*github.Client, *github.ReleaseEvent) error) *Action {
+	a.onRelease = eventHandler
+	return a
+}
+
+// OnRepository Repository handler (event: repository).
+func (a *Action) OnRepository(eventHandler func(*github.Client, *github.RepositoryEvent) error) *Action {
+	a.onRepository = eventHandler
+	return a
+}
+
+// OnRepositoryAdvisory RepositoryAdvisory handler (event: repository_advisory).
+func (a *Action) OnRepositoryAdvisory(eventHandler func(*github.Client, *RepositoryAdvisoryEvent) error) *Action {
+	a.onRepositoryAdvisory = eventHandler
+	return a
+}
+
+// OnRepositoryDispatch RepositoryDispatch handler (event: repository_dispatch).
+func (a *Action) OnRepositoryDispatch(eventHandler func(*github.Client, *github.RepositoryDispatchEvent) error) *Action {
+	a.onRepositoryDispatch = eventHandler
+	return a
+}
+
+// OnRepositoryImport RepositoryImport handler (event: repository_import).
+func (a *Action) OnRepositoryImport(eventHandler func(*github.Client, *github.RepositoryImportEvent) error) *Action {
+	a.onRepositoryImport = eventHandler
+	return a
+}
+
+// OnRepositoryRuleset RepositoryRuleset handler (event: repository_ruleset).
+func (a *Action) OnRepositoryRuleset(eventHandler func(*github.Client, *github.RepositoryRulesetEvent) error) *Action {
+	a.onRepositoryRuleset = eventHandler
+	return a
+}
+
+// OnRepositoryVulnerabilityAlert RepositoryVulnerabilityAlert handler (event: repository_vulnerability_alert).
+func (a *Action) OnRepositoryVulnerabilityAlert(eventHandler func(*github.Client, *github.RepositoryVulnerabilityAlertEvent) error) *Action {
+	a.onRepositoryVulnerabilityAlert = eventHandler
+	return a
+}
+
+// OnSchedule Schedule handler (event: schedule).
+func (a *Action) OnSchedule(eventHandler func(*github.Client, *ScheduleEvent) error) *Action {
+	a.onSchedule = eventHandler
+	return a
+}
+
+// OnSecretScanningAlert SecretScanningAlert handler (event: secret_scanning_alert).
+func (a *Action) OnSecretScanningAlert(eventHandler func(*github.Client, *github.SecretScanningAlertEvent) error) *Action {
+	a.onSecretScanningAlert = eventHandler
+	return a
+}
+
+// OnSecretScanningAlertLocation SecretScanningAlertLocation handler (event: secret_scanning_alert_location).
+func (a *Action) OnSecretScanningAlertLocation(eventHandler func(*github.Client, *github.SecretScanningAlertLocationEvent) error) *Action {
+	a.onSecretScanningAlertLocation = eventHandler
+	return a
+}
+
+// OnSecurityAdvisory SecurityAdvisory handler (event: security_advisory).
+func (a *Action) OnSecurityAdvisory(eventHandler func(*github.Client, *github.SecurityAdvisoryEvent) error) *Action {
+	a.onSecurityAdvisory = eventHandler
+	return a
+}
+
+// OnSecurityAndAnalysis SecurityAndAnalysis handler (event: security_and_analysis).
+func (a *Action) OnSecurityAndAnalysis(eventHandler func(*github.Client, *github.SecurityAndAnalysisEvent) error) *Action {
+	a.onSecurityAndAnalysis = eventHandler
+	return a
+}
+
+// OnSponsorship Sponsorship handler (event: sponsorship).
+func (a *Action) OnSponsorship(eventHandler func(*github.Client, *github.SponsorshipEvent) error) *Action {
+	a.onSponsorship = eventHandler
+	return a
+}
+
+// OnStar Star handler (event: star).
+func (a *Action) OnStar(eventHandler func(*github.Client, *github.StarEvent) error) *Action {
+	a.onStar = eventHandler
+	return a
+}
+
+// OnStatus Status handler (event: status).
+func (a *Action) OnStatus(eventHandler func(*github.Client, *github.StatusEvent) error) *Action {
+	a.onStatus = eventHandler
+	return a
+}
+
+// OnTeam Team handler (event: team).
+func (a *Action) OnTeam(eventHandler func(*github.Client, *github.TeamEvent) error) *Action {
+	a.onTeam = eventHandler
+	return a
+}
+
+// OnTeamAdd TeamAdd handler (event: team_add).
+func (a *Action) OnTeamAdd(eventHandler func(*github.Client, *github.TeamAddEvent) error) *Action {
+	a.onTeamAdd = eventHandler
+	return a
+}
+
+// OnUser User handler (event: user).
+func (a *Action) OnUser(eventHandler func(*github.Client, *github.UserEvent) error) *Action {
+	a.onUser = eventHandler
+	return a
+}
+
+// OnWatch Watch handler (event: watch).
+func (a *Action) OnWatch(eventHandler func(*github.Client, *github.WatchEvent) error) *Action {
+	a.onWatch = eventHandler
+	return a
+}
+
+// OnWorkflowDispatch WorkflowDispatch handler (event: workflow_dispatch).
+func (a *Action) OnWorkflowDispatch(eventHandler func(*github.Client, *github.WorkflowDispatchEvent) error) *Action {
+	a.onWorkflowDispatch = eventHandler
+	return a
+}
+
+// OnWorkflowJob WorkflowJob handler (event: workflow_job).
+func (a *Action) OnWorkflowJob(eventHandler func(*github.Client, *github.WorkflowJobEvent) error) *Action {
+	a.onWorkflowJob = eventHandler
+	return a
+}
+
+// OnWorkflowRun WorkflowRun handler (event: workflow_run).
+func (a *Action) OnWorkflowRun(eventHandler func(*github.Client, *github.WorkflowRunEvent) error) *Action {
+	a.onWorkflowRun = eventHandler
+	return a
+}
+
+// handler returns the handler of the event, bound to the event.
+// The handler is nil when no handler is defined for the event,
+// and known is false when the type of the event is not supported.
+func (h *handlers) handler(event any) (handler func(*github.Client) error, known bool) {
+	switch evt := event.(type) {
+	case *github.BranchProtectionConfigurationEvent:
+		if h.onBranchProtectionConfiguration != nil {
+			return func(client *github.Client) error { return h.onBranchProtectionConfiguration(client, evt) }, true
+		}
+
+	case *github.BranchProtectionRuleEvent:
+		if h.onBranchProtectionRule != nil {
+			return func(client *github.Client) error { return h.onBranchProtectionRule(client, evt) }, true
+		}
+
+	case *github.CheckRunEvent:
+		if h.onCheckRun != nil {
+			return func(client *github.Client) error { return h.onCheckRun(client, evt) }, true
+		}
+
+	case *github.CheckSuiteEvent:
+		if h.onCheckSuite != nil {
+			return func(client *github.Client) error { return h.onCheckSuite(client, evt) }, true
+		}
+
+	case *ClassicProjectEvent:
+		if h.onClassicProject != nil {
+			return func(client *github.Client) error { return h.onClassicProject(client, evt) }, true
+		}
+
+	case *ClassicProjectCardEvent:
+		if h.onClassicProjectCard != nil {
+			return func(client *github.Client) error { return h.onClassicProjectCard(client, evt) }, true
+		}
+
+	case *ClassicProjectColumnEvent:
+		if h.onClassicProjectColumn != nil {
+			return func(client *github.Client) error { return h.onClassicProjectColumn(client, evt) }, true
+		}
+
+	case *github.CodeScanningAlertEvent:
+		if h.onCodeScanningAlert != nil {
+			return func(client *github.Client) error { return h.onCodeScanningAlert(client, evt) }, true
+		}
+
+	case *github.CommitCommentEvent:
+		if h.onCommitComment != nil {
+			return func(client *github.Client) error { return h.onCommitComment(client, evt) }, true
+		}
+
+	case *github.ContentReferenceEvent:
+		if h.onContentReference != nil {
+			return func(client *github.Client) error { return h.onContentReference(client, evt) }, true
+		}
+
+	case *github.CreateEvent:
+		if h.onCreate != nil {
+			return func(client *github.Client) error { return h.onCreate(client, evt) }, true
+		}
+
+	case *github.CustomPropertyEvent:
+		if h.onCustomProperty != nil {
+			return func(client *github.Client) error { return h.onCustomProperty(client, evt) }, true
+		}
+
+	case *github.CustomPropertyValuesEvent:
+		if h.onCustomPropertyValues != nil {
+			return func(client *github.Client) error { return h.onCustomPropertyValues(client, evt) }, true
+		}
+
+	case *github.DeleteEvent:
+		if h.onDelete != nil {
+			return func(client *github.Client) error { return h.onDelete(client, evt) }, true
+		}
+
+	case *github.DependabotAlertEvent:
+		if h.onDependabotAlert != nil {
+			return func(client *github.Client) error { return h.onDependabotAlert(client, evt) }, true
+		}
+
+	case *github.DeployKeyEvent:
+		if h.onDeployKey != nil {
+			return func(client *github.Client) error { return h.onDeployKey(client, evt) }, true
+		}
+
+	case *github.DeploymentEvent:
+		if h.onDeployment != nil {
+			return func(client *github.Client) error { return h.onDeployment(client, evt) }, true
+		}
+
+	case *github.DeploymentProtectionRuleEvent:
+		if h.onDeploymentProtectionRule != nil {
+			return func(client *github.Client) error { return h.onDeploymentProtectionRule(client, evt) }, true
+		}
+
+	case *github.DeploymentReviewEvent:
+		if h.onDeploymentReview != nil {
+			return func(client *github.Client) error { return h.onDeploymentReview(client, evt) }, true
+		}
+
+	case *github.DeploymentStatusEvent:
+		if h.onDeploymentStatus != nil {
+			return func(client *github.Client) error { return h.onDeploymentStatus(client, evt) }, true
+		}
+
+	case *github.DiscussionEvent:
+		if h.onDiscussion != nil {
+			return func(client *github.Client) error { return h.onDiscussion(client, evt) }, true
+		}
+
+	case *github.DiscussionCommentEvent:
+		if h.onDiscussionComment != nil {
+			return func(client *github.Client) error { return h.onDiscussionComment(client, evt) }, true
+		}
+
+	case *github.ForkEvent:
+		if h.onFork != nil {
+			return func(client *github.Client) error { return h.onFork(client, evt) }, true
+		}
+
+	case *github.GitHubAppAuthorizationEvent:
+		if h.onGitHubAppAuthorization != nil {
+			return func(client *github.Client) error { return h.onGitHubAppAuthorization(client, evt) }, true
+		}
+
+	case *github.GollumEvent:
+		if h.onGollum != nil {
+			return func(client *github.Client) error { return h.onGollum(client, evt) }, true
+		}
+
+	case *github.InstallationEvent:
+		if h.onInstallation != nil {
+			return func(client *github.Client) error { return h.onInstallation(client, evt) }, true
+		}
+
+	case *github.InstallationRepositoriesEvent:
+		if h.onInstallationRepositories != nil {
+			return func(client *github.Client) error { return h.onInstallationRepositories(client, evt) }, true
+		}
+
+	case *github.InstallationTargetEvent:
+		if h.onInstallationTarget != nil {
+			return func(client *github.Client) error { return h.onInstallationTarget(client, evt) }, true
+		}
+
+	case *github.IssueCommentEvent:
+		if h.onIssueComment != nil {
+			return func(client *github.Client) error { return h.onIssueComment(client, evt) }, true
+		}
+
+	case *github.IssuesEvent:
+		if h.onIssues != nil {
+			return func(client *github.Client) error { return h.onIssues(client, evt) }, true
+		}
+
+	case *github.LabelEvent:
+		if h.onLabel != nil {
+			return func(client *github.Client) error { return h.onLabel(client, evt) }, true
+		}
+
+	case *github.MarketplacePurchaseEvent:
+		if h.onMarketplacePurchase != nil {
+			return func(client *github.Client) error { return h.onMarketplacePurchase(client, evt) }, true
+		}
+
+	case *github.MemberEvent:
+		if h.onMember != nil {
+			return func(client *github.Client) error { return h.onMember(client, evt) }, true
+		}
+
+	case *github.MembershipEvent:
+		if h.onMembership != nil {
+			return func(client *github.Client) error { return h.onMembership(client, evt) }, true
+		}
+
+	case *github.MergeGroupEvent:
+		if h.onMergeGroup != nil {
+			return func(client *github.Client) error { return h.onMergeGroup(client, evt) }, true
+		}
+
+	case *github.MetaEvent:
+		if h.onMeta != nil {
+			return func(client *github.Client) error { return h.onMeta(client, evt) }, true
+		}
+
+	case *github.MilestoneEvent:
+		if h.onMilestone != nil {
+			return func(client *github.Client) error { return h.onMilestone(client, evt) }, true
+		}
+
+	case *github.OrgBlockEvent:
+		if h.onOrgBlock != nil {
+			return func(client *github.Client) error { return h.onOrgBlock(client, evt) }, true
+		}
+
+	case *github.OrganizationEvent:
+		if h.onOrganization != nil {
+			return func(client *github.Client) error { return h.onOrganization(client, evt) }, true
+		}
+
+	case *github.PackageEvent:
+		if h.onPackage != nil {
+			return func(client *github.Client) error { return h.onPackage(client, evt) }, true
+		}
+
+	case *github.PageBuildEvent:
+		if h.onPageBuild != nil {
+			return func(client *github.Client) error { return h.onPageBuild(client, evt) }, true
+		}
+
+	case *github.PersonalAccessTokenRequestEvent:
+		if h.onPersonalAccessTokenRequest != nil {
+			return func(client *github.Client) error { return h.onPersonalAccessTokenRequest(client, evt) }, true
+		}
+
+	case *github.PingEvent:
+		if h.onPing != nil {
+			return func(client *github.Client) error { return h.onPing(client, evt) }, true
+		}
+
+	case *github.ProjectV2Event:
+		if h.onProject != nil {
+			return func(client *github.Client) error { return h.onProject(client, evt) }, true
+		}
+
+	case *github.ProjectV2ItemEvent:
+		if h.onProjectItem != nil {
+			return func(client *github.Client) error { return h.onProjectItem(client, evt) }, true
+		}
+
+	case *github.PublicEvent:
+		if h.onPublic != nil {
+			return func(client *github.Client) error { return h.onPublic(client, evt) }, true
+		}
+
+	case *github.PullRequestEvent:
+		if h.onPullRequest != nil {
+			return func(client *github.Client) error { return h.onPullRequest(client, evt) }, true
+		}
+
+	case *github.PullRequestReviewEvent:
+		if h.onPullRequestReview != nil {
+			return func(client *github.Client) error { return h.onPullRequestReview(client, evt) }, true
+		}
+
+	case *github.PullRequestReviewCommentEvent:
+		if h.onPullRequestReviewComment != nil {
+			return func(client *github.Client) error { return h.onPullRequestReviewComment(client, evt) }, true
+		}
+
+	case *github.PullRequestReviewThreadEvent:
+		if h.onPullRequestReviewThread != nil {
+			return func(client *github.Client) error { return h.onPullRequestReviewThread(client, evt) }, true
+		}
+
+	case *github.PullRequestTargetEvent:
+		if h.onPullRequestTarget != nil {
+			return func(client *github.Client) error { return h.onPullRequestTarget(client, evt) }, true
+		}
+
+	case *github.PushEvent:
+		if h.onPush != nil {
+			return func(client *github.Client) error { return h.onPush(client, evt) }, true
+		}
+
+	case *RegistryPackageEvent:
+		if h.onRegistryPackage != nil {
+			return func(client *github.Client) error { return h.onRegistryPackage(client, evt) }, true
+		}
+
+	case *github.ReleaseEvent:
+		if h.onRelease != nil {
+			return func(client *github.Client) error { return h.onRelease(client, evt) }, true
+		}
+
+	case *github.RepositoryEvent:
+		if h.onRepository != nil {
+			return func(client *github.Client) error { return h.onRepository(client, evt) }, true
+		}
+
+	case *RepositoryAdvisoryEvent:
+		if h.onRepositoryAdvisory != nil {
+			return func(client *github.Client) error { return h.onRepositoryAdvisory(client, evt) }, true
+		}
+
+	case *github.RepositoryDispatchEvent:
+		if h.onRepositoryDispatch != nil {
+			return func(client *github.Client) error { return h.onRepositoryDispatch(client, evt) }, true
+		}
+
+	case *github.RepositoryImportEvent:
+		if h.onRepositoryImport != nil {
+			return func(client *github.Client) error { return h.onRepositoryImport(client, evt) }, true
+		}
+
+	case *github.RepositoryRulesetEvent:
+		if h.onRepositoryRuleset != nil {
+			return func(client *github.Client) error { return h.onRepositoryRuleset(client, evt) }, true
+		}
+
+	case *github.RepositoryVulnerabilityAlertEvent:
+		if h.onRepositoryVulnerabilityAlert != nil {
+			return func(client *github.Client) error { return h.onRepositoryVulnerabilityAlert(client, evt) }, true
+		}
+
+	case *ScheduleEvent:
+		if h.onSchedule != nil {
+			return func(client *github.Client) error { return h.onSchedule(client, evt) }, true
+		}
+
+	case *github.SecretScanningAlertEvent:
+		if h.onSecretScanningAlert != nil {
+			return func(client *github.Client) error { return h.onSecretScanningAlert(client, evt) }, true
+		}
+
+	case *github.SecretScanningAlertLocationEvent:
+		if h.onSecretScanningAlertLocation != nil {
+			return func(client *github.Client) error { return h.onSecretScanningAlertLocation(client, evt) }, true
+		}
+
+	case *github.SecurityAdvisoryEvent:
+		if h.onSecurityAdvisory != nil {
+			return func(client *github.Client) error { return h.onSecurityAdvisory(client, evt) }, true
+		}
+
+	case *github.SecurityAndAnalysisEvent:
+		if h.onSecurityAndAnalysis != nil {
+			return func(client *github.Client) error { return h.onSecurityAndAnalysis(client, evt) }, true
+		}
+
+	case *github.SponsorshipEvent:
+		if h.onSponsorship != nil {
+			return func(client *github.Client) error { return h.onSponsorship(client, evt) }, true
+		}
+
+	case *github.StarEvent:
+		if h.onStar != nil {
+			return func(client *github.Client) error { return h.onStar(client, evt) }, true
+		}
+
+	case *github.StatusEvent:
+		if h.onStatus != nil {
+			return func(client *github.Client) error { return h.onStatus(client, evt) }, true
+		}
+
+	case *github.TeamEvent:
+		if h.onTeam != nil {
+			return func(client *github.Client) error { return h.onTeam(client, evt) }, true
+		}
+
+	case *github.TeamAddEvent:
+		if h.onTeamAdd != nil {
+			return func(client *github.Client) error { return h.onTeamAdd(client, evt) }, true
+		}
+
+	case *github.UserEvent:
+		if h.onUser != nil {
+			return func(client *github.Client) error { return h.onUser(client, evt) }, true
+		}
+
+	case *github.WatchEvent:
+		if h.onWatch != nil {
+			return func(client *github.Client) error { return h.onWatch(client, evt) }, true
+		}
+
+	case *github.WorkflowDispatchEvent:
+		if h.onWorkflowDispatch != nil {
+			return func(client *github.Client) error { return h.onWorkflowDispatch(client, evt) }, true
+		}
+
+	case *github.WorkflowJobEvent:
+		if h.onWorkflowJob != nil {
+			return func(client *github.Client) error { return h.onWorkflowJob(client, evt) }, true
+		}
+
+	case *github.WorkflowRunEvent:
+		if h.onWorkflowRun != nil {
+			return func(client *github.Client) error { return h.onWorkflowRun(client, evt) }, true
+		}
+
+	default:
+		return nil, false
+	}
+
+	return nil, true
+}
@@ -2,9 +2,14 @@ package ghactions
 
 import (
 	"context"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/google/go-github/v71/github"
+	"github.com/ldez/ghactions/internal/events"
 )
 
 func TestAction(t *testing.T) {
@@ -28,3 +33,264 @@ func TestAction(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestAction_Handle(t *testing.T) {
+	ctx := context.Background()
+
+	var called bool
+
+	action := NewAction(ctx).
+		OnRepositoryDispatch(func(_ *github.Client, _ *github.RepositoryDispatchEvent) error {
+			called = true
+			return nil
+		})
+
+	err := action.Handle(ctx, "repository_dispatch", []byte(`{"action":"deploy"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !called {
+		t.Error("the handler has not been called")
+	}
+
+	err = action.Handle(ctx, "unknown", []byte(`{}`))
+	if err == nil {
+		t.Error("expected an error for an unknown event type")
+	}
+
+	action.SkipWhenTypeUnknown = true
+
+	err = action.Handle(ctx, "unknown", []byte(`{}`))
+	if err != nil {
+		t.Errorf("unknown event type: %v", err)
+	}
+}
+
+func TestAction_schedule(t *testing.T) {
+	t.Setenv(GithubEventName, "schedule")
+	t.Setenv(GithubEventPath, "./fixtures/schedule.json")
+
+	var schedule string
+
+	err := NewAction(context.Background()).
+		OnSchedule(func(_ *github.Client, event *ScheduleEvent) error {
+			schedule = event.GetSchedule()
+			return nil
+		}).
+		Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if schedule != "30 5 * * 1-5" {
+		t.Errorf("got %q, want %q", schedule, "30 5 * * 1-5")
+	}
+}
+
+// TestAction_workflowCall runs an action inside a reusable workflow:
+// the event is the event of the caller, and the inputs of the reusable workflow are action inputs.
+func TestAction_workflowCall(t *testing.T) {
+	type inputs struct {
+		DryRun bool   `json:"dry-run"`
+		Label  string `json:"label"`
+		Count  int    `json:"count"`
+		Unset  string `json:"unset"`
+	}
+
+	// the payload of the caller (a push).
+	writeEvent(t, "push", `{"ref":"refs/heads/main","after":"abc","repository":{"full_name":"ldez/ghactions"}}`)
+
+	t.Setenv("INPUT_DRY-RUN", "true")
+	t.Setenv("INPUT_LABEL", "bug")
+	t.Setenv("INPUT_COUNT", "2")
+
+	var (
+		got inputs
+		ref string
+	)
+
+	action := NewAction(context.Background()).
+		OnPush(func(_ *github.Client, event *github.PushEvent) error {
+			ref = event.GetRef()
+			return DecodeActionInputs(&got)
+		})
+
+	action.Logger = discardLogger()
+
+	err := action.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := inputs{DryRun: true, Label: "bug", Count: 2}
+	if got != expected {
+		t.Errorf("got %+v, want %+v", got, expected)
+	}
+
+	if ref != "refs/heads/main" {
+		t.Errorf("got ref %q, want the ref of the caller", ref)
+	}
+
+	err = NewAction(context.Background()).Handle(context.Background(), "workflow_call", []byte(`{}`))
+	if err == nil {
+		t.Error("expected an error: workflow_call is never the name of the event")
+	}
+}
+
+// TestAction_OnWorkflowCall checks the dispatch to OnWorkflowCall inside a reusable workflow (the input workflow-call-inputs).
+func TestAction_OnWorkflowCall(t *testing.T) {
+	type inputs struct {
+		DryRun bool   `json:"dry-run"`
+		Count  int    `json:"count"`
+		Label  string `json:"label"`
+	}
+
+	// the payload of the caller (a merge queue).
+	payload := `{"action":"checks_requested","merge_group":{"head_sha":"abc","head_ref":"refs/heads/gh-readonly-queue/main/pr-1"}}`
+
+	testCases := []struct {
+		desc          string
+		callInputs    string
+		expected      *inputs
+		expectedEvent bool
+		expectedErr   bool
+	}{
+		{
+			desc:       "reusable workflow",
+			callInputs: `{"dry-run":true,"count":2,"label":"bug"}`,
+			expected:   &inputs{DryRun: true, Count: 2, Label: "bug"},
+		},
+		{
+			desc:          "not a reusable workflow",
+			expectedEvent: true,
+		},
+		{
+			desc:        "invalid inputs",
+			callInputs:  `{"dry-run":`,
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Setenv("INPUT_WORKFLOW-CALL-INPUTS", test.callInputs)
+
+			var (
+				got        *inputs
+				mergeGroup bool
+				headSHA    string
+			)
+
+			action := NewAction(context.Background()).
+				OnMergeGroup(func(_ *github.Client, _ *github.MergeGroupEvent) error {
+					mergeGroup = true
+					return nil
+				}).
+				OnWorkflowCall(func(_ *github.Client, event *WorkflowCallEvent) error {
+					if evt, ok := event.Event.(*github.MergeGroupEvent); ok && event.EventName == "merge_group" {
+						headSHA = evt.GetMergeGroup().GetHeadSHA()
+					}
+
+					got = &inputs{}
+
+					return event.DecodeInputs(got)
+				})
+
+			action.Logger = discardLogger()
+
+			err := action.Handle(context.Background(), "merge_group", []byte(payload))
+			if test.expectedErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if mergeGroup != test.expectedEvent {
+				t.Errorf("got handler of the event called %v, want %v", mergeGroup, test.expectedEvent)
+			}
+
+			if test.expected == nil {
+				if got != nil {
+					t.Errorf("got %+v, want OnWorkflowCall not called", got)
+				}
+
+				return
+			}
+
+			if got == nil || *got != *test.expected {
+				t.Errorf("got %+v, want %+v", got, test.expected)
+			}
+
+			if headSHA != "abc" {
+				t.Errorf("got head SHA %q, want the merge group of the caller", headSHA)
+			}
+		})
+	}
+}
+
+// TestAction_allEvents checks that all the events (parsed by go-github or by ghactions) have a handler.
+func TestAction_allEvents(t *testing.T) {
+	ctx := context.Background()
+
+	actionType := reflect.TypeOf(&Action{})
+
+	setters := map[reflect.Type]reflect.Method{}
+
+	for i := range actionType.NumMethod() {
+		method := actionType.Method(i)
+		if !strings.HasPrefix(method.Name, "On") || method.Type.NumIn() < 2 || method.Type.In(1).Kind() != reflect.Func {
+			continue
+		}
+
+		fn := method.Type.In(1)
+		if fn.NumIn() == 2 {
+			setters[fn.In(1)] = method
+		}
+	}
+
+	// the events not parsed by github.ParseWebHook (the workflow_call payload is the payload of the caller).
+	extras := slices.Sorted(maps.Keys(events.Local))
+
+	for _, name := range append(github.MessageTypes(), extras...) {
+		payload, err := parseEvent(name, []byte(`{}`))
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+
+		eventType := reflect.TypeOf(payload)
+
+		setter, ok := setters[eventType]
+		if !ok {
+			t.Errorf("%s: no handler for %s", name, eventType)
+			continue
+		}
+
+		var called bool
+
+		handler := reflect.MakeFunc(setter.Type.In(1), func(_ []reflect.Value) []reflect.Value {
+			called = true
+			return []reflect.Value{reflect.Zero(reflect.TypeFor[error]())}
+		})
+
+		action := NewAction(ctx)
+		setter.Func.Call([]reflect.Value{reflect.ValueOf(action), handler})
+
+		err = action.Handle(ctx, name, []byte(`{}`))
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+
+		if !called {
+			t.Errorf("%s: the handler %s has not been called", name, setter.Name)
+		}
+	}
+}
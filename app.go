@@ -0,0 +1,231 @@
+package ghactions
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v71/github"
+	"golang.org/x/oauth2"
+)
+
+// AppInstallations Creates clients authenticated as the installations of a GitHub App.
+// The installation tokens are cached until they expire.
+type AppInstallations struct {
+	appID int64
+	key   *rsa.PrivateKey
+
+	baseURL   string
+	uploadURL string
+
+	appClient *github.Client
+
+	mu      sync.Mutex
+	clients map[int64]*github.Client
+	login   string
+}
+
+// NewAppInstallations Creates a new client factory for the installations of a GitHub App.
+// privateKey is the PEM encoded private key of the App.
+func NewAppInstallations(appID int64, privateKey []byte) (*AppInstallations, error) {
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &AppInstallations{
+		appID:   appID,
+		key:     key,
+		clients: make(map[int64]*github.Client),
+	}
+
+	a.appClient = github.NewClient(&http.Client{Transport: &appTransport{app: a}})
+
+	return a, nil
+}
+
+// WithEnterpriseURLs Configures the clients for a GitHub Enterprise Server.
+func (a *AppInstallations) WithEnterpriseURLs(baseURL, uploadURL string) (*AppInstallations, error) {
+	client, err := a.appClient.WithEnterpriseURLs(baseURL, uploadURL)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.appClient = client
+	a.baseURL = baseURL
+	a.uploadURL = uploadURL
+	a.clients = make(map[int64]*github.Client)
+
+	return a, nil
+}
+
+// Client Returns a client authenticated for the installation that sent the event.
+// It can be used as Action.ClientFactory.
+// The App client (authenticated with a JWT) is returned when the event is not related to an installation.
+func (a *AppInstallations) Client(_ context.Context, event any) (*github.Client, error) {
+	evt, ok := event.(interface{ GetInstallation() *github.Installation })
+	if !ok || evt.GetInstallation().GetID() == 0 {
+		return a.appClient, nil
+	}
+
+	return a.InstallationClient(evt.GetInstallation().GetID())
+}
+
+// InstallationClient Returns a client authenticated for an installation.
+func (a *AppInstallations) InstallationClient(installationID int64) (*github.Client, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if client, ok := a.clients[installationID]; ok {
+		return client, nil
+	}
+
+	ts := oauth2.ReuseTokenSourceWithExpiry(nil, &installationTokenSource{
+		client: a.appClient,
+		id:     installationID,
+	}, time.Minute)
+
+	client := github.NewClient(oauth2.NewClient(context.Background(), ts))
+
+	if a.baseURL != "" {
+		var err error
+		client, err = client.WithEnterpriseURLs(a.baseURL, a.uploadURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	a.clients[installationID] = client
+
+	return client, nil
+}
+
+// Login Returns the login of the App, used as author by the installations: <app-slug>[bot].
+// Ex: WithCommentAuthor.
+func (a *AppInstallations) Login(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	login, client := a.login, a.appClient
+	a.mu.Unlock()
+
+	if login != "" {
+		return login, nil
+	}
+
+	app, _, err := client.Apps.Get(ctx, "")
+	if err != nil {
+		return "", fmt.Errorf("get the App: %w", err)
+	}
+
+	if app.GetSlug() == "" {
+		return "", errors.New("get the App: no slug")
+	}
+
+	login = app.GetSlug() + "[bot]"
+
+	a.mu.Lock()
+	a.login = login
+	a.mu.Unlock()
+
+	return login, nil
+}
+
+// jwt creates a JSON Web Token to authenticate as the App.
+func (a *AppInstallations) jwt(now time.Time) (string, error) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+
+	claims, err := json.Marshal(map[string]any{
+		// to allow for clock drift.
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(a.appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(unsigned))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("sign JWT: %w", err)
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+type appTransport struct {
+	app *AppInstallations
+}
+
+func (t *appTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.app.jwt(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+type installationTokenSource struct {
+	client *github.Client
+	id     int64
+}
+
+func (s *installationTokenSource) Token() (*oauth2.Token, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	token, _, err := s.client.Apps.CreateInstallationToken(ctx, s.id, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create installation token (%d): %w", s.id, err)
+	}
+
+	return &oauth2.Token{
+		AccessToken: token.GetToken(),
+		TokenType:   "Bearer",
+		Expiry:      token.GetExpiresAt().Time,
+	}, nil
+}
+
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid private key: no PEM data")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("invalid private key: not a RSA key")
+	}
+
+	return rsaKey, nil
+}
@@ -0,0 +1,108 @@
+package ghactions
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v71/github"
+)
+
+func TestAppInstallations(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	var tokenCalls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v3/app/installations/42/access_tokens", func(rw http.ResponseWriter, req *http.Request) {
+		if !strings.HasPrefix(req.Header.Get("Authorization"), "Bearer ") {
+			http.Error(rw, "missing JWT", http.StatusUnauthorized)
+			return
+		}
+
+		tokenCalls.Add(1)
+
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"token":"ghs_42","expires_at":"` + time.Now().Add(time.Hour).Format(time.RFC3339) + `"}`))
+	})
+	mux.HandleFunc("GET /api/v3/user", func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer ghs_42" {
+			http.Error(rw, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = rw.Write([]byte(`{"login":"bot"}`))
+	})
+	mux.HandleFunc("GET /api/v3/app", func(rw http.ResponseWriter, req *http.Request) {
+		if !strings.HasPrefix(req.Header.Get("Authorization"), "Bearer ") || req.Header.Get("Authorization") == "Bearer ghs_42" {
+			http.Error(rw, "missing JWT", http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = rw.Write([]byte(`{"id":1,"slug":"my-app"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	apps, err := NewAppInstallations(1, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apps, err = apps.WithEnterpriseURLs(server.URL, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	action := NewAction(context.Background())
+	action.ClientFactory = apps.Client
+
+	payload := []byte(`{"action":"opened","installation":{"id":42}}`)
+
+	for range 2 {
+		err = action.
+			OnIssues(func(client *github.Client, _ *github.IssuesEvent) error {
+				user, _, err := client.Users.Get(context.Background(), "")
+				if err != nil {
+					return err
+				}
+
+				if user.GetLogin() != "bot" {
+					t.Errorf("got %q, want bot", user.GetLogin())
+				}
+
+				return nil
+			}).
+			Handle(context.Background(), "issues", payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if tokenCalls.Load() != 1 {
+		t.Errorf("got %d token creations, want 1", tokenCalls.Load())
+	}
+
+	login, err := apps.Login(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if login != "my-app[bot]" {
+		t.Errorf("got login %q, want %q", login, "my-app[bot]")
+	}
+}
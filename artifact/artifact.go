@@ -0,0 +1,457 @@
+// Package artifact Uploads and downloads the artifacts of the workflow runs with the artifact service of the runner
+// (the service used by actions/upload-artifact@v4 and actions/download-artifact@v4).
+//
+// The client reads ACTIONS_RESULTS_URL and ACTIONS_RUNTIME_TOKEN,
+// these variables are only available inside the actions (not inside the `run` steps).
+// The artifacts are zip files.
+package artifact
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ldez/ghactions"
+)
+
+// Environment variables of the artifact service.
+const (
+	EnvResultsURL    = "ACTIONS_RESULTS_URL"
+	EnvRuntimeToken  = "ACTIONS_RUNTIME_TOKEN"
+	EnvRetentionDays = "GITHUB_RETENTION_DAYS"
+)
+
+// artifactVersion the version of the artifacts (v4 artifacts).
+const artifactVersion = 4
+
+// servicePath the path of the Twirp service.
+const servicePath = "twirp/github.actions.results.api.v1.ArtifactService/"
+
+// ErrNotFound The artifact does not exist.
+var ErrNotFound = errors.New("artifact not found")
+
+// Artifact An artifact of the current workflow run.
+type Artifact struct {
+	ID        int64
+	Name      string
+	Size      int64
+	Digest    string
+	CreatedAt time.Time
+}
+
+// Option An option of the client.
+type Option func(*Client)
+
+// WithHTTPClient Defines the HTTP client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// Client A client of the artifact service, for the artifacts of the current workflow run.
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+
+	runBackendID string
+	jobBackendID string
+}
+
+// NewClient Creates a client from the environment variables (ACTIONS_RESULTS_URL, ACTIONS_RUNTIME_TOKEN).
+func NewClient(opts ...Option) (*Client, error) {
+	baseURL := os.Getenv(EnvResultsURL)
+	if baseURL == "" {
+		return nil, fmt.Errorf("%s is not defined: the artifact service is only available inside an action", EnvResultsURL)
+	}
+
+	token := os.Getenv(EnvRuntimeToken)
+	if token == "" {
+		return nil, fmt.Errorf("%s is not defined", EnvRuntimeToken)
+	}
+
+	return NewClientWithURL(baseURL, token, opts...)
+}
+
+// NewClientWithURL Creates a client of an artifact service.
+// The IDs of the workflow run and the job are read from the token.
+func NewClientWithURL(baseURL, token string, opts ...Option) (*Client, error) {
+	runID, jobID, err := backendIDs(token)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		baseURL:      strings.TrimSuffix(baseURL, "/") + "/",
+		token:        token,
+		httpClient:   http.DefaultClient,
+		runBackendID: runID,
+		jobBackendID: jobID,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// UploadOption An option of Upload.
+type UploadOption func(*uploadConfig)
+
+type uploadConfig struct {
+	retentionDays    int
+	compressionLevel int
+}
+
+// WithRetentionDays Defines the number of days before the artifact expires
+// (the retention of the repository by default, limited by GITHUB_RETENTION_DAYS).
+func WithRetentionDays(days int) UploadOption {
+	return func(cfg *uploadConfig) {
+		cfg.retentionDays = days
+	}
+}
+
+// WithCompressionLevel Defines the compression level of the zip, from 0 (no compression) to 9 (6 by default).
+func WithCompressionLevel(level int) UploadOption {
+	return func(cfg *uploadConfig) {
+		cfg.compressionLevel = level
+	}
+}
+
+// Upload Uploads files (or directories) as an artifact, and returns the artifact.
+// The names of the files inside the zip are relative to the root directory.
+func (c *Client) Upload(ctx context.Context, name string, files []string, rootDir string, opts ...UploadOption) (*Artifact, error) {
+	cfg := &uploadConfig{compressionLevel: 6}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	err := validateName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("upload the artifact %q: no files", name)
+	}
+
+	archive, err := os.CreateTemp("", "artifact-*.zip")
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = archive.Close()
+		_ = os.Remove(archive.Name())
+	}()
+
+	hash := sha256.New()
+
+	err = createZip(io.MultiWriter(archive, hash), files, rootDir, cfg.compressionLevel)
+	if err != nil {
+		return nil, fmt.Errorf("create the zip of the artifact %q: %w", name, err)
+	}
+
+	size, err := archive.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+
+	createReq := map[string]any{
+		"workflow_run_backend_id":     c.runBackendID,
+		"workflow_job_run_backend_id": c.jobBackendID,
+		"name":                        name,
+		"version":                     artifactVersion,
+	}
+
+	if days := retentionDays(cfg.retentionDays); days > 0 {
+		createReq["expires_at"] = time.Now().AddDate(0, 0, days).UTC().Format(time.RFC3339)
+	}
+
+	var created struct {
+		OK              bool   `json:"ok"`
+		SignedUploadURL string `json:"signed_upload_url"`
+	}
+
+	err = c.call(ctx, "CreateArtifact", createReq, &created)
+	if err != nil {
+		return nil, err
+	}
+
+	if !created.OK {
+		return nil, fmt.Errorf("create the artifact %q: refused by the service", name)
+	}
+
+	_, err = archive.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.uploadBlob(ctx, created.SignedUploadURL, archive, size)
+	if err != nil {
+		return nil, fmt.Errorf("upload the artifact %q: %w", name, err)
+	}
+
+	digest := "sha256:" + hex.EncodeToString(hash.Sum(nil))
+
+	var finalized struct {
+		OK         bool  `json:"ok"`
+		ArtifactID int64 `json:"artifact_id,string"`
+	}
+
+	err = c.call(ctx, "FinalizeArtifact", map[string]any{
+		"workflow_run_backend_id":     c.runBackendID,
+		"workflow_job_run_backend_id": c.jobBackendID,
+		"name":                        name,
+		"size":                        strconv.FormatInt(size, 10),
+		"hash":                        digest,
+	}, &finalized)
+	if err != nil {
+		return nil, err
+	}
+
+	if !finalized.OK {
+		return nil, fmt.Errorf("finalize the artifact %q: refused by the service", name)
+	}
+
+	return &Artifact{ID: finalized.ArtifactID, Name: name, Size: size, Digest: digest, CreatedAt: time.Now()}, nil
+}
+
+// List Lists the artifacts of the current workflow run.
+func (c *Client) List(ctx context.Context) ([]Artifact, error) {
+	return c.list(ctx, "")
+}
+
+// Get Gets an artifact of the current workflow run by name.
+// Returns ErrNotFound when the artifact does not exist.
+func (c *Client) Get(ctx context.Context, name string) (*Artifact, error) {
+	artifacts, err := c.list(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(artifacts) == 0 {
+		return nil, fmt.Errorf("%s: %w", name, ErrNotFound)
+	}
+
+	// the newest artifact (an artifact can be uploaded again by a re-run).
+	latest := artifacts[0]
+	for _, a := range artifacts[1:] {
+		if a.CreatedAt.After(latest.CreatedAt) {
+			latest = a
+		}
+	}
+
+	return &latest, nil
+}
+
+// Download Downloads an artifact of the current workflow run, and extracts it into dest (GITHUB_WORKSPACE when empty).
+// The digest of the artifact is verified.
+func (c *Client) Download(ctx context.Context, name, dest string) error {
+	artifact, err := c.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	var signed struct {
+		SignedURL string `json:"signed_url"`
+	}
+
+	err = c.call(ctx, "GetSignedArtifactURL", map[string]any{
+		"workflow_run_backend_id":     c.runBackendID,
+		"workflow_job_run_backend_id": c.jobBackendID,
+		"name":                        name,
+	}, &signed)
+	if err != nil {
+		return err
+	}
+
+	return downloadZip(ctx, c.httpClient, signed.SignedURL, artifact.Digest, dest)
+}
+
+func (c *Client) list(ctx context.Context, name string) ([]Artifact, error) {
+	req := map[string]any{
+		"workflow_run_backend_id":     c.runBackendID,
+		"workflow_job_run_backend_id": c.jobBackendID,
+	}
+
+	if name != "" {
+		req["name_filter"] = name
+	}
+
+	var resp struct {
+		Artifacts []struct {
+			DatabaseID int64     `json:"database_id,string"`
+			Name       string    `json:"name"`
+			Size       int64     `json:"size,string"`
+			Digest     string    `json:"digest"`
+			CreatedAt  time.Time `json:"created_at"`
+		} `json:"artifacts"`
+	}
+
+	err := c.call(ctx, "ListArtifacts", req, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts := make([]Artifact, 0, len(resp.Artifacts))
+
+	for _, a := range resp.Artifacts {
+		artifacts = append(artifacts, Artifact{
+			ID:        a.DatabaseID,
+			Name:      a.Name,
+			Size:      a.Size,
+			Digest:    a.Digest,
+			CreatedAt: a.CreatedAt,
+		})
+	}
+
+	return artifacts, nil
+}
+
+// uploadBlob uploads the zip to the signed URL (Azure Blob Storage).
+// The signed URL contains the credentials: the token is not sent.
+func (c *Client) uploadBlob(ctx context.Context, signedURL string, body io.Reader, size int64) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, signedURL, body)
+	if err != nil {
+		return err
+	}
+
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/zip")
+	req.Header.Set("X-Ms-Blob-Type", "BlockBlob")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return statusError("upload the zip", resp)
+	}
+
+	return nil
+}
+
+// call calls a method of the Twirp service (JSON).
+func (c *Client) call(ctx context.Context, method string, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+servicePath+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return statusError(method, resp)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(out)
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+
+	return nil
+}
+
+// backendIDs reads the IDs of the workflow run and the job from the scope of the token (JWT):
+// "Actions.Results:<run backend ID>:<job backend ID>".
+func backendIDs(token string) (string, string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", "", errors.New("invalid runtime token: not a JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return "", "", fmt.Errorf("invalid runtime token: %w", err)
+	}
+
+	var claims struct {
+		Scope string `json:"scp"`
+	}
+
+	err = json.Unmarshal(payload, &claims)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid runtime token: %w", err)
+	}
+
+	for _, scope := range strings.Fields(claims.Scope) {
+		values := strings.Split(scope, ":")
+		if len(values) == 3 && values[0] == "Actions.Results" {
+			return values[1], values[2], nil
+		}
+	}
+
+	return "", "", errors.New("invalid runtime token: no Actions.Results scope")
+}
+
+// retentionDays limits the retention to GITHUB_RETENTION_DAYS (the maximum retention of the repository), like the toolkit.
+func retentionDays(days int) int {
+	if days <= 0 {
+		return 0
+	}
+
+	maxDays, err := strconv.Atoi(os.Getenv(EnvRetentionDays))
+	if err != nil || maxDays <= 0 {
+		return days
+	}
+
+	return min(days, maxDays)
+}
+
+// validateName validates the name of an artifact, with the rules of the toolkit.
+func validateName(name string) error {
+	if name == "" {
+		return errors.New("the name of the artifact is empty")
+	}
+
+	if strings.ContainsAny(name, "\":<>|*?\r\n\\/") {
+		return fmt.Errorf("invalid name of artifact %q: the characters \" : < > | * ? \\r \\n \\ / are not allowed", name)
+	}
+
+	return nil
+}
+
+// workspace returns GITHUB_WORKSPACE, or the current directory.
+func workspace() string {
+	if dir := os.Getenv(ghactions.GithubWorkspace); dir != "" {
+		return dir
+	}
+
+	return "."
+}
+
+func statusError(action string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	return fmt.Errorf("%s: unexpected status code %d: %s", action, resp.StatusCode, strings.TrimSpace(string(body)))
+}
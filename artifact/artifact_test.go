@@ -0,0 +1,207 @@
+package artifact
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ldez/ghactions"
+	"github.com/ldez/ghactions/artifact/artifacttest"
+)
+
+func newTestClient(t *testing.T) (*Client, *artifacttest.Server) {
+	t.Helper()
+
+	server := artifacttest.NewServer()
+	t.Cleanup(server.Close)
+
+	t.Setenv(EnvResultsURL, server.URL+"/")
+	t.Setenv(EnvRuntimeToken, server.Token)
+	t.Setenv(ghactions.GithubWorkspace, t.TempDir())
+
+	client, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client, server
+}
+
+func TestClient_Upload_Download(t *testing.T) {
+	client, server := newTestClient(t)
+
+	root := t.TempDir()
+
+	writeFile(t, filepath.Join(root, "report.sarif"), `{"version":"2.1.0"}`)
+	writeFile(t, filepath.Join(root, "coverage", "cover.out"), "mode: set")
+	writeFile(t, filepath.Join(root, "other.txt"), "ignored")
+
+	files := []string{filepath.Join(root, "report.sarif"), filepath.Join(root, "coverage")}
+
+	uploaded, err := client.Upload(context.Background(), "reports", files, root, WithRetentionDays(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored, ok := server.Get("reports")
+	if !ok {
+		t.Fatal("artifact not stored")
+	}
+
+	if uploaded.ID != stored.ID || uploaded.Digest != stored.Digest || uploaded.Size != stored.Size {
+		t.Errorf("got %+v, want the stored artifact %+v", uploaded, stored)
+	}
+
+	if stored.ExpiresAt.Sub(time.Now().AddDate(0, 0, 3)).Abs() > time.Minute {
+		t.Errorf("got expiration %s, want in 3 days", stored.ExpiresAt)
+	}
+
+	_, err = client.Upload(context.Background(), "reports", files, root)
+	if err == nil {
+		t.Error("expected an error: the artifact already exists")
+	}
+
+	artifacts, err := client.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(artifacts) != 1 || artifacts[0].Name != "reports" {
+		t.Errorf("got %+v", artifacts)
+	}
+
+	dest := t.TempDir()
+
+	err = client.Download(context.Background(), "reports", dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"report.sarif":       `{"version":"2.1.0"}`,
+		"coverage/cover.out": "mode: set",
+	}
+
+	for name, content := range expected {
+		data, err := os.ReadFile(filepath.Join(dest, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(data) != content {
+			t.Errorf("%s: got %q, want %q", name, data, content)
+		}
+	}
+
+	_, err = os.Stat(filepath.Join(dest, "other.txt"))
+	if !os.IsNotExist(err) {
+		t.Error("other.txt should not be in the artifact")
+	}
+}
+
+func TestClient_Get_notFound(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	_, err := client.Get(context.Background(), "missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want ErrNotFound", err)
+	}
+
+	err = client.Download(context.Background(), "missing", t.TempDir())
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want ErrNotFound", err)
+	}
+}
+
+func TestClient_Upload_invalidName(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	_, err := client.Upload(context.Background(), "a/b", []string{"a"}, ".")
+	if err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func Test_backendIDs(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		token   string
+		run     string
+		job     string
+		invalid bool
+	}{
+		{
+			desc:  "valid",
+			token: artifacttest.NewToken("run", "job"),
+			run:   "run",
+			job:   "job",
+		},
+		{
+			desc:    "not a JWT",
+			token:   "ghs_xxx",
+			invalid: true,
+		},
+		{
+			desc:    "invalid payload",
+			token:   "a.!!!.c",
+			invalid: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			run, job, err := backendIDs(test.token)
+			if test.invalid {
+				if err == nil {
+					t.Error("expected an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if run != test.run || job != test.job {
+				t.Errorf("got %s/%s, want %s/%s", run, job, test.run, test.job)
+			}
+		})
+	}
+}
+
+func Test_retentionDays(t *testing.T) {
+	t.Setenv(EnvRetentionDays, "30")
+
+	testCases := []struct {
+		days     int
+		expected int
+	}{
+		{days: 0, expected: 0},
+		{days: 7, expected: 7},
+		{days: 90, expected: 30},
+	}
+
+	for _, test := range testCases {
+		if got := retentionDays(test.days); got != test.expected {
+			t.Errorf("retentionDays(%d): got %d, want %d", test.days, got, test.expected)
+		}
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(path, []byte(content), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
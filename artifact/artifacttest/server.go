@@ -0,0 +1,336 @@
+// Package artifacttest A local stand-in of the artifact service of the runner (v4 artifacts), to test the artifact client offline.
+package artifacttest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Backend IDs of the workflow run and the job of the token.
+const (
+	RunBackendID = "run-backend-id"
+	JobBackendID = "job-backend-id"
+)
+
+const servicePath = "/twirp/github.actions.results.api.v1.ArtifactService/"
+
+// Artifact An artifact stored by the server.
+type Artifact struct {
+	ID        int64
+	Name      string
+	Size      int64
+	Digest    string
+	ExpiresAt time.Time
+	Content   []byte
+	Finalized bool
+	CreatedAt time.Time
+}
+
+// Server A fake artifact service.
+type Server struct {
+	*httptest.Server
+
+	// Token The runtime token expected by the server (a JWT with the scope of the backend IDs).
+	Token string
+
+	mu        sync.Mutex
+	nextID    int64
+	artifacts []*Artifact
+}
+
+// NewServer Starts a fake artifact service, the caller must call Close.
+func NewServer() *Server {
+	s := &Server{Token: NewToken(RunBackendID, JobBackendID), nextID: 1}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+servicePath+"CreateArtifact", s.authorized(s.create))
+	mux.HandleFunc("POST "+servicePath+"FinalizeArtifact", s.authorized(s.finalize))
+	mux.HandleFunc("POST "+servicePath+"ListArtifacts", s.authorized(s.list))
+	mux.HandleFunc("POST "+servicePath+"GetSignedArtifactURL", s.authorized(s.signedURL))
+	mux.HandleFunc("PUT /blobs/{id}", s.putBlob)
+	mux.HandleFunc("GET /blobs/{id}", s.getBlob)
+
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// NewToken Creates an unsigned runtime token (JWT) with the scope "Actions.Results:<runID>:<jobID>".
+func NewToken(runID, jobID string) string {
+	encode := func(v any) string {
+		data, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	return encode(map[string]string{"alg": "none", "typ": "JWT"}) + "." +
+		encode(map[string]string{"scp": "Actions.GenericRead:abc Actions.Results:" + runID + ":" + jobID}) + ".signature"
+}
+
+// Get Gets a finalized artifact by name.
+func (s *Server) Get(name string) (Artifact, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a := s.find(name)
+	if a == nil {
+		return Artifact{}, false
+	}
+
+	return *a, true
+}
+
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer "+s.Token {
+			twirpError(rw, http.StatusUnauthorized, "unauthenticated", "invalid token")
+			return
+		}
+
+		next(rw, req)
+	}
+}
+
+type backendIDs struct {
+	RunBackendID string `json:"workflow_run_backend_id"`
+	JobBackendID string `json:"workflow_job_run_backend_id"`
+}
+
+func (b backendIDs) valid() bool {
+	return b.RunBackendID == RunBackendID && b.JobBackendID == JobBackendID
+}
+
+func (s *Server) create(rw http.ResponseWriter, req *http.Request) {
+	var body struct {
+		backendIDs
+
+		Name      string     `json:"name"`
+		Version   int        `json:"version"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+
+	err := json.NewDecoder(req.Body).Decode(&body)
+	if err != nil || !body.valid() || body.Name == "" || body.Version != 4 {
+		twirpError(rw, http.StatusBadRequest, "invalid_argument", "invalid request")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.find(body.Name) != nil {
+		twirpError(rw, http.StatusConflict, "already_exists", "an artifact with this name already exists on the workflow run")
+		return
+	}
+
+	artifact := &Artifact{ID: s.nextID, Name: body.Name}
+	if body.ExpiresAt != nil {
+		artifact.ExpiresAt = *body.ExpiresAt
+	}
+
+	s.nextID++
+	s.artifacts = append(s.artifacts, artifact)
+
+	writeJSON(rw, map[string]any{"ok": true, "signed_upload_url": fmt.Sprintf("%s/blobs/%d?sig=upload", s.URL, artifact.ID)})
+}
+
+func (s *Server) finalize(rw http.ResponseWriter, req *http.Request) {
+	var body struct {
+		backendIDs
+
+		Name string `json:"name"`
+		Size int64  `json:"size,string"`
+		Hash string `json:"hash"`
+	}
+
+	err := json.NewDecoder(req.Body).Decode(&body)
+	if err != nil || !body.valid() {
+		twirpError(rw, http.StatusBadRequest, "invalid_argument", "invalid request")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := slices.IndexFunc(s.artifacts, func(a *Artifact) bool { return a.Name == body.Name && !a.Finalized })
+	if idx < 0 {
+		twirpError(rw, http.StatusNotFound, "not_found", "artifact not found")
+		return
+	}
+
+	artifact := s.artifacts[idx]
+
+	hash := sha256.Sum256(artifact.Content)
+	digest := "sha256:" + hex.EncodeToString(hash[:])
+
+	if body.Size != int64(len(artifact.Content)) || body.Hash != digest {
+		twirpError(rw, http.StatusBadRequest, "invalid_argument", "the size or the hash does not match the upload")
+		return
+	}
+
+	artifact.Size = body.Size
+	artifact.Digest = digest
+	artifact.Finalized = true
+	artifact.CreatedAt = time.Now()
+
+	writeJSON(rw, map[string]any{"ok": true, "artifact_id": strconv.FormatInt(artifact.ID, 10)})
+}
+
+func (s *Server) list(rw http.ResponseWriter, req *http.Request) {
+	var body struct {
+		backendIDs
+
+		NameFilter string `json:"name_filter"`
+	}
+
+	err := json.NewDecoder(req.Body).Decode(&body)
+	if err != nil || !body.valid() {
+		twirpError(rw, http.StatusBadRequest, "invalid_argument", "invalid request")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	artifacts := []map[string]any{}
+
+	for _, a := range s.artifacts {
+		if !a.Finalized || (body.NameFilter != "" && a.Name != body.NameFilter) {
+			continue
+		}
+
+		artifacts = append(artifacts, map[string]any{
+			"workflow_run_backend_id":     RunBackendID,
+			"workflow_job_run_backend_id": JobBackendID,
+			"database_id":                 strconv.FormatInt(a.ID, 10),
+			"name":                        a.Name,
+			"size":                        strconv.FormatInt(a.Size, 10),
+			"digest":                      a.Digest,
+			"created_at":                  a.CreatedAt.UTC().Format(time.RFC3339Nano),
+		})
+	}
+
+	writeJSON(rw, map[string]any{"artifacts": artifacts})
+}
+
+func (s *Server) signedURL(rw http.ResponseWriter, req *http.Request) {
+	var body struct {
+		backendIDs
+
+		Name string `json:"name"`
+	}
+
+	err := json.NewDecoder(req.Body).Decode(&body)
+	if err != nil || !body.valid() {
+		twirpError(rw, http.StatusBadRequest, "invalid_argument", "invalid request")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	artifact := s.find(body.Name)
+	if artifact == nil {
+		twirpError(rw, http.StatusNotFound, "not_found", "artifact not found")
+		return
+	}
+
+	writeJSON(rw, map[string]any{"signed_url": fmt.Sprintf("%s/blobs/%d?sig=download", s.URL, artifact.ID)})
+}
+
+// putBlob stores the content of an artifact, like the Put Blob operation of Azure Blob Storage.
+func (s *Server) putBlob(rw http.ResponseWriter, req *http.Request) {
+	if req.URL.Query().Get("sig") != "upload" || req.Header.Get("X-Ms-Blob-Type") != "BlockBlob" {
+		http.Error(rw, "invalid signature or blob type", http.StatusForbidden)
+		return
+	}
+
+	if req.Header.Get("Authorization") != "" {
+		http.Error(rw, "unexpected Authorization header", http.StatusBadRequest)
+		return
+	}
+
+	content, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	artifact := s.byID(req.PathValue("id"))
+	if artifact == nil || artifact.Finalized {
+		http.NotFound(rw, req)
+		return
+	}
+
+	artifact.Content = content
+
+	rw.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) getBlob(rw http.ResponseWriter, req *http.Request) {
+	if req.URL.Query().Get("sig") != "download" {
+		http.Error(rw, "invalid signature", http.StatusForbidden)
+		return
+	}
+
+	s.mu.Lock()
+	artifact := s.byID(req.PathValue("id"))
+	s.mu.Unlock()
+
+	if artifact == nil || !artifact.Finalized {
+		http.NotFound(rw, req)
+		return
+	}
+
+	http.ServeContent(rw, req, "", artifact.CreatedAt, bytes.NewReader(artifact.Content))
+}
+
+// find finds a finalized artifact by name.
+func (s *Server) find(name string) *Artifact {
+	idx := slices.IndexFunc(s.artifacts, func(a *Artifact) bool { return a.Name == name && a.Finalized })
+	if idx < 0 {
+		return nil
+	}
+
+	return s.artifacts[idx]
+}
+
+func (s *Server) byID(rawID string) *Artifact {
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	idx := slices.IndexFunc(s.artifacts, func(a *Artifact) bool { return a.ID == id })
+	if idx < 0 {
+		return nil
+	}
+
+	return s.artifacts[idx]
+}
+
+func twirpError(rw http.ResponseWriter, status int, code, msg string) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+
+	_ = json.NewEncoder(rw).Encode(map[string]string{"code": code, "msg": msg})
+}
+
+func writeJSON(rw http.ResponseWriter, value any) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	_ = json.NewEncoder(rw).Encode(value)
+}
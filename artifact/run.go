@@ -0,0 +1,93 @@
+package artifact
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/ldez/ghactions"
+)
+
+// DownloadFromRun Downloads an artifact of another workflow run (e.g. the run of a workflow_run event) with the REST API,
+// and extracts it into dest (GITHUB_WORKSPACE when empty).
+// The digest of the artifact is verified (the artifacts uploaded before the digests are not verified).
+// The token of the client needs the `actions: read` permission.
+func DownloadFromRun(ctx context.Context, client *github.Client, repo ghactions.Repo, runID int64, name, dest string) error {
+	artifact, err := findRunArtifact(ctx, client, repo, runID, name)
+	if err != nil {
+		return err
+	}
+
+	location, _, err := client.Actions.DownloadArtifact(ctx, repo.Owner, repo.Name, artifact.GetID(), 0)
+	if err != nil {
+		return fmt.Errorf("get the download URL of the artifact %q: %w", name, err)
+	}
+
+	// the URL is signed: the client of the API is not used, the token is not sent to the storage.
+	return downloadZip(ctx, http.DefaultClient, location.String(), artifact.GetDigest(), dest)
+}
+
+// runArtifact an artifact of the REST API with its digest (not defined by go-github).
+type runArtifact struct {
+	*github.Artifact
+
+	Digest *string `json:"digest,omitempty"`
+}
+
+// GetDigest returns the digest ("sha256:<hex>"), or an empty string.
+func (a *runArtifact) GetDigest() string {
+	if a == nil || a.Digest == nil {
+		return ""
+	}
+
+	return *a.Digest
+}
+
+// findRunArtifact finds the newest artifact (not expired) of a run by name.
+func findRunArtifact(ctx context.Context, client *github.Client, repo ghactions.Repo, runID int64, name string) (*runArtifact, error) {
+	var found *runArtifact
+
+	page := 1
+
+	for {
+		// the list of the artifacts of go-github does not contain the digests.
+		u := fmt.Sprintf("repos/%s/%s/actions/runs/%d/artifacts?per_page=100&page=%d", repo.Owner, repo.Name, runID, page)
+
+		req, err := client.NewRequest(http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var list struct {
+			Artifacts []*runArtifact `json:"artifacts"`
+		}
+
+		resp, err := client.Do(ctx, req, &list)
+		if err != nil {
+			return nil, fmt.Errorf("list the artifacts of the run %d: %w", runID, err)
+		}
+
+		for _, a := range list.Artifacts {
+			if a.Artifact == nil || a.GetName() != name || a.GetExpired() {
+				continue
+			}
+
+			if found == nil || a.GetCreatedAt().After(found.GetCreatedAt().Time) {
+				found = a
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		page = resp.NextPage
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("%s (run %d): %w", name, runID, ErrNotFound)
+	}
+
+	return found, nil
+}
@@ -0,0 +1,101 @@
+package artifact
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/ldez/ghactions"
+)
+
+func TestDownloadFromRun(t *testing.T) {
+	archive := &bytes.Buffer{}
+
+	zw := zip.NewWriter(archive)
+
+	w, err := zw.Create("dist/app.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = w.Write([]byte("app"))
+
+	err = zw.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sum := sha256.Sum256(archive.Bytes())
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	mux := http.NewServeMux()
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("GET /api/v3/repos/owner/repo/actions/runs/42/artifacts", func(rw http.ResponseWriter, _ *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(rw, `{"total_count":4,"artifacts":[
+			{"id":1,"name":"dist","expired":false,"created_at":"2024-01-01T00:00:00Z"},
+			{"id":2,"name":"dist","expired":false,"created_at":"2024-01-02T00:00:00Z","digest":%q},
+			{"id":3,"name":"dist","expired":true,"created_at":"2024-01-03T00:00:00Z"},
+			{"id":4,"name":"tampered","expired":false,"created_at":"2024-01-03T00:00:00Z","digest":"sha256:00"}
+		]}`, digest)
+	})
+
+	mux.HandleFunc("GET /api/v3/repos/owner/repo/actions/artifacts/{id}/zip", func(rw http.ResponseWriter, req *http.Request) {
+		http.Redirect(rw, req, server.URL+"/signed/"+req.PathValue("id"), http.StatusFound)
+	})
+
+	mux.HandleFunc("GET /signed/{id}", func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "" {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		_, _ = rw.Write(archive.Bytes())
+	})
+
+	client, err := github.NewClient(nil).WithAuthToken("secret").WithEnterpriseURLs(server.URL, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := ghactions.Repo{Owner: "owner", Name: "repo"}
+	dest := t.TempDir()
+
+	err = DownloadFromRun(context.Background(), client, repo, 42, "dist", dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dest, "dist", "app.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != "app" {
+		t.Errorf("got %q, want app", data)
+	}
+
+	err = DownloadFromRun(context.Background(), client, repo, 42, "missing", dest)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want ErrNotFound", err)
+	}
+
+	err = DownloadFromRun(context.Background(), client, repo, 42, "tampered", t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "digest mismatch") {
+		t.Errorf("got %v, want a digest mismatch", err)
+	}
+}
@@ -0,0 +1,226 @@
+package artifact
+
+import (
+	"archive/zip"
+	"compress/flate"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ldez/ghactions/internal/safepath"
+)
+
+// createZip creates a zip of files (or directories), the names are relative to the root directory.
+func createZip(w io.Writer, files []string, rootDir string, level int) error {
+	root, err := filepath.Abs(rootDir)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+
+	method := zip.Deflate
+	if level == 0 {
+		method = zip.Store
+	}
+
+	zw.RegisterCompressor(zip.Deflate, func(out io.Writer) (io.WriteCloser, error) {
+		return flate.NewWriter(out, level)
+	})
+
+	seen := map[string]bool{}
+
+	for _, file := range files {
+		path, err := filepath.Abs(file)
+		if err != nil {
+			return err
+		}
+
+		err = filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			name, err := filepath.Rel(root, path)
+			if err != nil || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+				return fmt.Errorf("%s is not inside the root directory %s", path, rootDir)
+			}
+
+			name = filepath.ToSlash(name)
+			if name == "." || seen[name] {
+				return nil
+			}
+
+			seen[name] = true
+
+			return addToZip(zw, path, name, d, method)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func addToZip(zw *zip.Writer, path, name string, d fs.DirEntry, method uint16) error {
+	info, err := d.Info()
+	if err != nil {
+		return err
+	}
+
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+
+	header.Name = name
+
+	if d.IsDir() {
+		header.Name += "/"
+
+		_, err = zw.CreateHeader(header)
+
+		return err
+	}
+
+	if !info.Mode().IsRegular() {
+		// ignores the other types (e.g. the sockets), the symbolic links are followed by Stat.
+		info, err = os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			return nil
+		}
+	}
+
+	header.Method = method
+
+	dst, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+
+	src, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = src.Close() }()
+
+	_, err = io.Copy(dst, src)
+
+	return err
+}
+
+// downloadZip downloads a zip from a signed URL, verifies its digest ("sha256:<hex>", ignored when empty),
+// and extracts it into dest (GITHUB_WORKSPACE when empty).
+func downloadZip(ctx context.Context, client *http.Client, signedURL, digest, dest string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, signedURL, http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("download the artifact: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return statusError("download the artifact", resp)
+	}
+
+	archive, err := os.CreateTemp("", "artifact-*.zip")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = archive.Close()
+		_ = os.Remove(archive.Name())
+	}()
+
+	hash := sha256.New()
+
+	size, err := io.Copy(io.MultiWriter(archive, hash), resp.Body)
+	if err != nil {
+		return fmt.Errorf("download the artifact: %w", err)
+	}
+
+	if actual := "sha256:" + hex.EncodeToString(hash.Sum(nil)); digest != "" && !strings.EqualFold(actual, digest) {
+		return fmt.Errorf("digest mismatch of the artifact: got %s, want %s", actual, digest)
+	}
+
+	if dest == "" {
+		dest = workspace()
+	}
+
+	return extractZip(archive, size, dest)
+}
+
+// extractZip extracts a zip, the entries outside the destination and the entries through a link are refused.
+func extractZip(r io.ReaderAt, size int64, dest string) error {
+	reader, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("extract the artifact: %w", err)
+	}
+
+	dest, err = filepath.Abs(dest)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range reader.File {
+		err = extractZipFile(f, dest)
+		if err != nil {
+			return fmt.Errorf("extract the artifact: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func extractZipFile(f *zip.File, dest string) error {
+	// the links of the destination (or created by a previous artifact) are never followed.
+	target, err := safepath.Target(dest, filepath.FromSlash(f.Name))
+	if err != nil {
+		return err
+	}
+
+	if f.FileInfo().IsDir() {
+		return os.MkdirAll(target, 0o755)
+	}
+
+	err = os.MkdirAll(filepath.Dir(target), 0o755)
+	if err != nil {
+		return err
+	}
+
+	src, err := f.Open()
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = src.Close() }()
+
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, f.Mode().Perm()|0o600)
+	if err != nil {
+		return err
+	}
+
+	//nolint:gosec // the artifacts are created by the workflows of the repository.
+	_, err = io.Copy(file, src)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,80 @@
+package artifact
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_createZip_outsideRoot(t *testing.T) {
+	root := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "a.txt")
+
+	writeFile(t, outside, "a")
+
+	err := createZip(&bytes.Buffer{}, []string{outside}, root, 6)
+	if err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func Test_extractZip_invalidPath(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	zw := zip.NewWriter(buf)
+
+	w, err := zw.Create("../evil.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = w.Write([]byte("evil"))
+
+	err = zw.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = extractZip(bytes.NewReader(buf.Bytes()), int64(buf.Len()), t.TempDir())
+	if err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func Test_extractZip_link(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	zw := zip.NewWriter(buf)
+
+	w, err := zw.Create("out/evil.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = w.Write([]byte("evil"))
+
+	err = zw.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dest := t.TempDir()
+	outside := t.TempDir()
+
+	// a link of the destination pointing outside.
+	err = os.Symlink(outside, filepath.Join(dest, "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = extractZip(bytes.NewReader(buf.Bytes()), int64(buf.Len()), dest)
+	if err == nil {
+		t.Error("expected an error")
+	}
+
+	if _, err = os.Stat(filepath.Join(outside, "evil.txt")); err == nil {
+		t.Error("the file has been written through the link")
+	}
+}
@@ -0,0 +1,251 @@
+package cache
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ldez/ghactions"
+	"github.com/ldez/ghactions/internal/safepath"
+)
+
+// createArchive creates a tar archive compressed with zstd.
+// The names are relative to the workspace (like the toolkit), the paths outside the workspace start with "../".
+// The compression is the compression of the toolkit (`zstd -T0`, level 3, without the long distance matching):
+// the window (8MB) is small enough to be decompressed by `zstd -d` without `--long`.
+func createArchive(w io.Writer, paths []string, workspace string) error {
+	enc, err := zstd.NewWriter(w, zstd.WithEncoderLevel(zstd.SpeedDefault), zstd.WithWindowSize(8<<20))
+	if err != nil {
+		return err
+	}
+
+	tw := tar.NewWriter(enc)
+
+	for _, p := range paths {
+		err = addToArchive(tw, resolvePath(p, workspace), workspace)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+func addToArchive(tw *tar.Writer, root, workspace string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		var link string
+		if info.Mode()&fs.ModeSymlink != 0 {
+			link, err = os.Readlink(path)
+			if err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(workspace, path)
+		if err != nil {
+			return err
+		}
+
+		header.Name = filepath.ToSlash(name)
+		if d.IsDir() {
+			header.Name += "/"
+		}
+
+		err = tw.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = file.Close() }()
+
+		_, err = io.Copy(tw, file)
+
+		return err
+	})
+}
+
+// extractArchive extracts a tar archive compressed with zstd, the names are relative to the workspace.
+// The entries must be inside one of the paths of the cache, with the extraction rules of the tool cache:
+// no entry through a link, no link outside the path.
+func extractArchive(r io.Reader, paths []string, workspace string) error {
+	roots := make([]string, 0, len(paths))
+	for _, p := range paths {
+		roots = append(roots, resolvePath(p, workspace))
+	}
+
+	dec, err := zstd.NewReader(r)
+	if err != nil {
+		return err
+	}
+
+	defer dec.Close()
+
+	tr := tar.NewReader(dec)
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		err = extractEntry(tr, header, roots, workspace)
+		if err != nil {
+			return fmt.Errorf("%s: %w", header.Name, err)
+		}
+	}
+}
+
+func extractEntry(tr *tar.Reader, header *tar.Header, roots []string, workspace string) error {
+	root, rel, err := entryPath(header.Name, roots, workspace)
+	if err != nil {
+		return err
+	}
+
+	switch header.Typeflag {
+	case tar.TypeDir:
+		target, err := safepath.Target(root, rel)
+		if err != nil {
+			return err
+		}
+
+		return os.MkdirAll(target, header.FileInfo().Mode().Perm()|0o700)
+
+	case tar.TypeSymlink:
+		err := safepath.Link(root, rel, header.Linkname)
+		if err != nil {
+			return err
+		}
+
+		// the link itself can replace an existing link (a cache restored twice), not its parents.
+		parent, err := safepath.Target(root, filepath.Dir(rel))
+		if err != nil {
+			return err
+		}
+
+		err = os.MkdirAll(parent, 0o755)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(parent, filepath.Base(rel))
+
+		_ = os.Remove(target)
+
+		return os.Symlink(header.Linkname, target)
+
+	case tar.TypeReg:
+		target, err := safepath.Target(root, rel)
+		if err != nil {
+			return err
+		}
+
+		err = os.MkdirAll(filepath.Dir(target), 0o755)
+		if err != nil {
+			return err
+		}
+
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, header.FileInfo().Mode().Perm())
+		if err != nil {
+			return err
+		}
+
+		//nolint:gosec // the caches are created by the workflows of the repository.
+		_, err = io.Copy(file, tr)
+		if err != nil {
+			_ = file.Close()
+			return err
+		}
+
+		return file.Close()
+
+	default:
+		return nil
+	}
+}
+
+// entryPath returns the path of the cache containing an archive entry, and the path of the entry relative to it.
+func entryPath(name string, roots []string, workspace string) (string, string, error) {
+	target := filepath.Join(workspace, filepath.FromSlash(name))
+
+	for _, root := range roots {
+		if !safepath.Within(root, target) {
+			continue
+		}
+
+		rel, err := filepath.Rel(root, target)
+		if err != nil {
+			return "", "", err
+		}
+
+		return root, rel, nil
+	}
+
+	return "", "", fmt.Errorf("invalid path in the archive: %s (outside the paths of the cache)", name)
+}
+
+// resolvePath resolves a path of a cache: `~` is the home directory, the relative paths are relative to the workspace.
+func resolvePath(p, workspace string) string {
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			p = filepath.Join(home, p[1:])
+		}
+	}
+
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(workspace, p)
+	}
+
+	return filepath.Clean(p)
+}
+
+// workspace returns GITHUB_WORKSPACE, or the current directory.
+func workspace() string {
+	if dir := os.Getenv(ghactions.GithubWorkspace); dir != "" {
+		return dir
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+
+	return dir
+}
@@ -0,0 +1,216 @@
+package cache
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func Test_createArchive_extractArchive(t *testing.T) {
+	root := t.TempDir()
+
+	ws := filepath.Join(root, "workspace")
+	outside := filepath.Join(root, "outside")
+
+	writeFile(t, filepath.Join(ws, "dir", "a.txt"), "a")
+	writeFile(t, filepath.Join(outside, "b.txt"), "b")
+
+	err := os.Symlink("a.txt", filepath.Join(ws, "dir", "link"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archive := &bytes.Buffer{}
+
+	err = createArchive(archive, []string{"dir", outside}, ws)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.RemoveAll(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = extractArchive(archive, []string{"dir", outside}, ws)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		filepath.Join(ws, "dir", "a.txt"): "a",
+		filepath.Join(ws, "dir", "link"):  "a",
+		filepath.Join(outside, "b.txt"):   "b",
+	}
+
+	for path, content := range expected {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(data) != content {
+			t.Errorf("%s: got %q, want %q", path, data, content)
+		}
+	}
+}
+
+func Test_extractArchive_unsafe(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		headers []*tar.Header
+	}{
+		{
+			desc:    "outside the paths",
+			headers: []*tar.Header{{Name: "other/x", Typeflag: tar.TypeReg, Mode: 0o644}},
+		},
+		{
+			desc:    "path traversal",
+			headers: []*tar.Header{{Name: "dir/../../x", Typeflag: tar.TypeReg, Mode: 0o644}},
+		},
+		{
+			desc:    "absolute link",
+			headers: []*tar.Header{{Name: "dir/link", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"}},
+		},
+		{
+			desc:    "link outside",
+			headers: []*tar.Header{{Name: "dir/link", Typeflag: tar.TypeSymlink, Linkname: "../x"}},
+		},
+		{
+			desc: "file through a link",
+			headers: []*tar.Header{
+				{Name: "dir/l", Typeflag: tar.TypeSymlink, Linkname: "."},
+				{Name: "dir/l/x", Typeflag: tar.TypeReg, Mode: 0o644},
+			},
+		},
+		{
+			desc: "file replacing a link",
+			headers: []*tar.Header{
+				{Name: "dir/l", Typeflag: tar.TypeSymlink, Linkname: "x"},
+				{Name: "dir/l", Typeflag: tar.TypeReg, Mode: 0o644},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			archive := &bytes.Buffer{}
+
+			enc, err := zstd.NewWriter(archive)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tw := tar.NewWriter(enc)
+
+			for _, header := range test.headers {
+				err = tw.WriteHeader(header)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_ = tw.Close()
+			_ = enc.Close()
+
+			ws := filepath.Join(t.TempDir(), "workspace")
+
+			err = extractArchive(archive, []string{"dir"}, ws)
+			if err == nil || !strings.Contains(err.Error(), "invalid") {
+				t.Errorf("got %v, want an error", err)
+			}
+
+			for _, path := range []string{filepath.Join(ws, "x"), filepath.Join(ws, "other", "x"), filepath.Join(filepath.Dir(ws), "x")} {
+				if _, err := os.Lstat(path); err == nil {
+					t.Errorf("%s: created outside the paths of the cache", path)
+				}
+			}
+		})
+	}
+}
+
+// Test_createArchive_compat checks that the archives can be extracted like actions/cache does (`tar --use-compress-program "zstd -d"`).
+func Test_createArchive_compat(t *testing.T) {
+	for _, name := range []string{"tar", "zstd"} {
+		if _, err := exec.LookPath(name); err != nil {
+			t.Skipf("%s is not available", name)
+		}
+	}
+
+	ws := t.TempDir()
+	writeFile(t, filepath.Join(ws, "dir", "a.txt"), strings.Repeat("a", 1<<20))
+
+	archive := filepath.Join(t.TempDir(), "cache.tzst")
+
+	file, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = createArchive(file, []string{"dir"}, ws)
+	_ = file.Close()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dest := t.TempDir()
+
+	output, err := exec.Command("tar", "-xf", archive, "-P", "-C", dest, "--use-compress-program", "zstd -d").CombinedOutput()
+	if err != nil {
+		t.Fatalf("tar: %v: %s", err, output)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dest, "dir", "a.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(data) != 1<<20 {
+		t.Errorf("got %d bytes, want %d", len(data), 1<<20)
+	}
+}
+
+func Test_resolvePath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	testCases := []struct {
+		desc     string
+		path     string
+		expected string
+	}{
+		{
+			desc:     "relative",
+			path:     "build/out",
+			expected: "/ws/build/out",
+		},
+		{
+			desc:     "absolute",
+			path:     "/tmp/out",
+			expected: "/tmp/out",
+		},
+		{
+			desc:     "home",
+			path:     "~/.cache/go-build",
+			expected: filepath.Join(home, ".cache", "go-build"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			got := resolvePath(test.path, "/ws")
+			if got != test.expected {
+				t.Errorf("got %q, want %q", got, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,552 @@
+// Package cache Saves and restores caches with the cache service of the runner (the service used by actions/cache@v4).
+//
+// The client uses the v2 service (Twirp, ACTIONS_RESULTS_URL) when ACTIONS_CACHE_SERVICE_V2 is defined,
+// the legacy service (ACTIONS_CACHE_URL) otherwise (e.g. GHES).
+// These variables, and ACTIONS_RUNTIME_TOKEN, are only available inside the actions (not inside the `run` steps).
+// The archives are tar files compressed with zstd, like the archives of actions/cache.
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Environment variables of the cache service.
+const (
+	EnvResultsURL     = "ACTIONS_RESULTS_URL"
+	EnvCacheURL       = "ACTIONS_CACHE_URL"
+	EnvCacheServiceV2 = "ACTIONS_CACHE_SERVICE_V2"
+	EnvRuntimeToken   = "ACTIONS_RUNTIME_TOKEN"
+)
+
+// Defaults.
+const (
+	DefaultChunkSize   = 32 << 20
+	DefaultConcurrency = 4
+)
+
+// servicePath the path of the Twirp service.
+const servicePath = "twirp/github.actions.results.api.v1.CacheService/"
+
+// compressionMethod the compression of the archives, part of the version of the caches:
+// zstd without the long distance matching (`zstdmt`, like the toolkit), see createArchive.
+const compressionMethod = "zstd-without-long"
+
+// versionSalt the salt of the version of the caches (same as the toolkit).
+const versionSalt = "1.0"
+
+// ErrCacheExists Another job is saving a cache with the same key, or the cache already exists.
+var ErrCacheExists = errors.New("the cache already exists")
+
+// Entry A cache entry.
+type Entry struct {
+	// Key the key of the cache (the matched key).
+	Key string
+	// Version the version of the cache (see Version).
+	Version string
+	// ArchiveLocation the signed URL of the archive.
+	ArchiveLocation string
+}
+
+// Option An option of the client.
+type Option func(*Client)
+
+// WithHTTPClient Defines the HTTP client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// WithChunkSize Defines the size of the chunks of the uploads and the downloads (DefaultChunkSize by default).
+func WithChunkSize(size int64) Option {
+	return func(c *Client) {
+		c.chunkSize = size
+	}
+}
+
+// WithConcurrency Defines the maximum number of chunks uploaded or downloaded in parallel (DefaultConcurrency by default).
+func WithConcurrency(concurrency int) Option {
+	return func(c *Client) {
+		c.concurrency = max(concurrency, 1)
+	}
+}
+
+// WithLegacyService Uses the legacy cache service (v1, the URL of ACTIONS_CACHE_URL) instead of the v2 service.
+func WithLegacyService() Option {
+	return func(c *Client) {
+		c.legacy = true
+	}
+}
+
+// Client A client of the cache service.
+type Client struct {
+	baseURL     string
+	token       string
+	httpClient  *http.Client
+	chunkSize   int64
+	concurrency int
+	legacy      bool
+}
+
+// NewClient Creates a client from the environment variables:
+//   - ACTIONS_RESULTS_URL (the v2 service) when ACTIONS_CACHE_SERVICE_V2 is defined, like the toolkit.
+//   - ACTIONS_CACHE_URL (the legacy service) otherwise.
+func NewClient(opts ...Option) (*Client, error) {
+	token := os.Getenv(EnvRuntimeToken)
+	if token == "" {
+		return nil, fmt.Errorf("%s is not defined", EnvRuntimeToken)
+	}
+
+	if os.Getenv(EnvCacheServiceV2) != "" {
+		baseURL := os.Getenv(EnvResultsURL)
+		if baseURL == "" {
+			return nil, fmt.Errorf("%s is not defined: the cache service is only available inside an action", EnvResultsURL)
+		}
+
+		return NewClientWithURL(baseURL, token, opts...), nil
+	}
+
+	baseURL := os.Getenv(EnvCacheURL)
+	if baseURL == "" {
+		return nil, fmt.Errorf("%s is not defined: the cache service is only available inside an action", EnvCacheURL)
+	}
+
+	return NewClientWithURL(baseURL, token, append([]Option{WithLegacyService()}, opts...)...), nil
+}
+
+// NewClientWithURL Creates a client of a cache service (the v2 service, see WithLegacyService).
+func NewClientWithURL(baseURL, token string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:     strings.TrimSuffix(baseURL, "/") + "/",
+		token:       token,
+		httpClient:  http.DefaultClient,
+		chunkSize:   DefaultChunkSize,
+		concurrency: DefaultConcurrency,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Version Returns the version of a cache of paths:
+// the caches are only restored for the same paths, the same compression, and the same OS family (Windows or not).
+func Version(paths []string) string {
+	components := append(append([]string{}, paths...), compressionMethod)
+
+	if runtime.GOOS == "windows" {
+		// like the toolkit (without enableCrossOsArchive).
+		components = append(components, "windows-only")
+	}
+
+	components = append(components, versionSalt)
+
+	hash := sha256.Sum256([]byte(strings.Join(components, "|")))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// Lookup Finds a cache entry: the key is matched exactly, then the restore keys are matched by prefix (in order).
+// Returns nil when no cache matches.
+func (c *Client) Lookup(ctx context.Context, paths []string, key string, restoreKeys ...string) (*Entry, error) {
+	version := Version(paths)
+
+	if c.legacy {
+		return c.lookupV1(ctx, version, key, restoreKeys)
+	}
+
+	var resp struct {
+		OK                bool   `json:"ok"`
+		SignedDownloadURL string `json:"signed_download_url"`
+		MatchedKey        string `json:"matched_key"`
+	}
+
+	err := c.call(ctx, "GetCacheEntryDownloadURL", map[string]any{
+		"key":          key,
+		"restore_keys": append([]string{}, restoreKeys...),
+		"version":      version,
+	}, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("lookup the cache: %w", err)
+	}
+
+	if !resp.OK || resp.SignedDownloadURL == "" {
+		return nil, nil //nolint:nilnil // cache miss.
+	}
+
+	return &Entry{Key: resp.MatchedKey, Version: version, ArchiveLocation: resp.SignedDownloadURL}, nil
+}
+
+// Restore Restores a cache of paths, and returns the key of the restored cache.
+// The key is matched exactly, then the restore keys are matched by prefix (in order).
+// Returns an empty key when no cache matches.
+func (c *Client) Restore(ctx context.Context, paths []string, key string, restoreKeys ...string) (string, error) {
+	entry, err := c.Lookup(ctx, paths, key, restoreKeys...)
+	if err != nil || entry == nil {
+		return "", err
+	}
+
+	archive, err := os.CreateTemp("", "cache-*.tar.zst")
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = archive.Close()
+		_ = os.Remove(archive.Name())
+	}()
+
+	err = c.download(ctx, entry.ArchiveLocation, archive)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = archive.Seek(0, io.SeekStart)
+	if err != nil {
+		return "", err
+	}
+
+	err = extractArchive(archive, paths, workspace())
+	if err != nil {
+		return "", fmt.Errorf("extract the cache %q: %w", entry.Key, err)
+	}
+
+	return entry.Key, nil
+}
+
+// Save Saves a cache of paths (files or directories).
+// Returns ErrCacheExists when the key is already used.
+func (c *Client) Save(ctx context.Context, paths []string, key string) error {
+	if len(paths) == 0 {
+		return errors.New("save the cache: no paths")
+	}
+
+	archive, err := os.CreateTemp("", "cache-*.tar.zst")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = archive.Close()
+		_ = os.Remove(archive.Name())
+	}()
+
+	err = createArchive(archive, paths, workspace())
+	if err != nil {
+		return fmt.Errorf("create the archive: %w", err)
+	}
+
+	info, err := archive.Stat()
+	if err != nil {
+		return err
+	}
+
+	version := Version(paths)
+
+	if c.legacy {
+		return c.saveV1(ctx, key, version, archive, info.Size())
+	}
+
+	uploadURL, err := c.reserve(ctx, key, version)
+	if err != nil {
+		return err
+	}
+
+	err = c.upload(ctx, uploadURL, archive, info.Size())
+	if err != nil {
+		return err
+	}
+
+	return c.commit(ctx, key, version, info.Size())
+}
+
+// reserve creates the cache entry, and returns the signed URL of the upload.
+func (c *Client) reserve(ctx context.Context, key, version string) (string, error) {
+	var resp struct {
+		OK              bool   `json:"ok"`
+		SignedUploadURL string `json:"signed_upload_url"`
+	}
+
+	err := c.call(ctx, "CreateCacheEntry", map[string]any{"key": key, "version": version}, &resp)
+	if err != nil {
+		return "", fmt.Errorf("reserve the cache %q: %w", key, err)
+	}
+
+	if !resp.OK {
+		// another job may be creating this cache.
+		return "", fmt.Errorf("reserve the cache %q: %w", key, ErrCacheExists)
+	}
+
+	return resp.SignedUploadURL, nil
+}
+
+// upload uploads the archive to the signed URL (Azure Blob Storage): the chunks are blocks uploaded in parallel,
+// then the list of the blocks is committed.
+// The signed URL contains the credentials: the token is not sent.
+func (c *Client) upload(ctx context.Context, uploadURL string, archive io.ReaderAt, size int64) error {
+	err := c.parallel(ctx, size, func(ctx context.Context, start, end int64) error {
+		query := url.Values{"comp": {"block"}, "blockid": {blockID(start)}}
+
+		return c.putBlob(ctx, uploadURL, query, io.NewSectionReader(archive, start, end-start+1), end-start+1, "application/octet-stream")
+	})
+	if err != nil {
+		return fmt.Errorf("upload a block of the cache: %w", err)
+	}
+
+	blocks := &bytes.Buffer{}
+	blocks.WriteString(`<?xml version="1.0" encoding="utf-8"?><BlockList>`)
+
+	for start := int64(0); start < size; start += c.chunkSize {
+		blocks.WriteString("<Latest>" + blockID(start) + "</Latest>")
+	}
+
+	blocks.WriteString("</BlockList>")
+
+	err = c.putBlob(ctx, uploadURL, url.Values{"comp": {"blocklist"}}, bytes.NewReader(blocks.Bytes()), int64(blocks.Len()), "application/xml")
+	if err != nil {
+		return fmt.Errorf("commit the blocks of the cache: %w", err)
+	}
+
+	return nil
+}
+
+// blockID the ID of the block starting at an offset (the IDs of a blob must have the same length).
+func blockID(start int64) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%020d", start)))
+}
+
+func (c *Client) putBlob(ctx context.Context, signedURL string, query url.Values, body io.Reader, size int64, contentType string) error {
+	u, err := url.Parse(signedURL)
+	if err != nil {
+		return fmt.Errorf("invalid signed URL: %w", err)
+	}
+
+	values := u.Query()
+	for key, value := range query {
+		values[key] = value
+	}
+
+	u.RawQuery = values.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), body)
+	if err != nil {
+		return err
+	}
+
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return statusError("upload the cache", resp)
+	}
+
+	return nil
+}
+
+// commit finalizes the cache entry.
+func (c *Client) commit(ctx context.Context, key, version string, size int64) error {
+	var resp struct {
+		OK bool `json:"ok"`
+	}
+
+	err := c.call(ctx, "FinalizeCacheEntryUpload", map[string]any{
+		"key":        key,
+		"version":    version,
+		"size_bytes": strconv.FormatInt(size, 10),
+	}, &resp)
+	if err != nil {
+		return fmt.Errorf("commit the cache %q: %w", key, err)
+	}
+
+	if !resp.OK {
+		return fmt.Errorf("commit the cache %q: refused by the service", key)
+	}
+
+	return nil
+}
+
+// download downloads the archive, by chunks in parallel when the size is known.
+func (c *Client) download(ctx context.Context, location string, dest *os.File) error {
+	size, err := c.archiveSize(ctx, location)
+	if err != nil || size <= 0 {
+		// the size is unknown: single download.
+		return c.downloadRange(ctx, location, dest, 0, -1)
+	}
+
+	return c.parallel(ctx, size, func(ctx context.Context, start, end int64) error {
+		return c.downloadRange(ctx, location, dest, start, end)
+	})
+}
+
+func (c *Client) archiveSize(ctx context.Context, location string) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, location, http.NoBody)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, statusError("get the size of the cache", resp)
+	}
+
+	return resp.ContentLength, nil
+}
+
+// downloadRange downloads a range of the archive (the whole archive when end is negative).
+// The archive location is a pre-signed URL: the token is not sent.
+func (c *Client) downloadRange(ctx context.Context, location string, dest io.WriterAt, start, end int64) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	if end >= 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("download the cache: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return statusError("download the cache", resp)
+	}
+
+	if end >= 0 && resp.StatusCode == http.StatusOK && start > 0 {
+		return errors.New("download the cache: the server does not support the ranges")
+	}
+
+	_, err = io.Copy(io.NewOffsetWriter(dest, start), resp.Body)
+	if err != nil {
+		return fmt.Errorf("download the cache: %w", err)
+	}
+
+	return nil
+}
+
+// parallel calls fn for each chunk [start, end] of size bytes, with at most concurrency calls in parallel.
+// The first error cancels the other calls.
+func (c *Client) parallel(ctx context.Context, size int64, fn func(ctx context.Context, start, end int64) error) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	sem := make(chan struct{}, c.concurrency)
+	errs := make(chan error, 1)
+
+	for start := int64(0); start < size; start += c.chunkSize {
+		end := min(start+c.chunkSize, size) - 1
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+
+		if ctx.Err() != nil {
+			// no new call, the running calls are awaited below.
+			break
+		}
+
+		go func() {
+			defer func() { <-sem }()
+
+			err := fn(ctx, start, end)
+			if err != nil {
+				cancel(err)
+
+				select {
+				case errs <- err:
+				default:
+				}
+			}
+		}()
+	}
+
+	// waits for the running calls.
+	for range c.concurrency {
+		sem <- struct{}{}
+	}
+
+	select {
+	case err := <-errs:
+		return err
+	default:
+		return context.Cause(ctx)
+	}
+}
+
+// call calls a method of the Twirp service (JSON).
+// A conflict (the Twirp code already_exists) is an ErrCacheExists.
+func (c *Client) call(ctx context.Context, method string, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+servicePath+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusConflict {
+		return fmt.Errorf("%s: %w", method, ErrCacheExists)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return statusError(method, resp)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(out)
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+
+	return nil
+}
+
+func statusError(action string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	return fmt.Errorf("%s: unexpected status code %d: %s", action, resp.StatusCode, strings.TrimSpace(string(body)))
+}
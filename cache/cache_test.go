@@ -0,0 +1,311 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ldez/ghactions"
+	"github.com/ldez/ghactions/cache/cachetest"
+)
+
+// services the versions of the cache service.
+var services = []string{"v1", "v2"}
+
+func newTestClient(t *testing.T, service string) (*Client, *cachetest.Server) {
+	t.Helper()
+
+	server := cachetest.NewServer()
+	t.Cleanup(server.Close)
+
+	if service == "v2" {
+		t.Setenv(EnvCacheServiceV2, "true")
+		t.Setenv(EnvResultsURL, server.URL+"/")
+		t.Setenv(EnvCacheURL, "")
+	} else {
+		t.Setenv(EnvCacheServiceV2, "")
+		t.Setenv(EnvResultsURL, "")
+		t.Setenv(EnvCacheURL, server.URL+"/")
+	}
+
+	t.Setenv(EnvRuntimeToken, cachetest.Token)
+	t.Setenv(ghactions.GithubWorkspace, t.TempDir())
+
+	// small chunks to test the parallel uploads and downloads.
+	client, err := NewClient(WithChunkSize(4096), WithConcurrency(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client, server
+}
+
+func TestClient_Save_Restore(t *testing.T) {
+	for _, service := range services {
+		t.Run(service, func(t *testing.T) {
+			client, server := newTestClient(t, service)
+
+			ws := os.Getenv(ghactions.GithubWorkspace)
+
+			// random content: the archive is not compressible, and contains several chunks.
+			data := make([]byte, 50_000)
+			_, _ = rand.Read(data)
+
+			writeFile(t, filepath.Join(ws, "build", "data.bin"), string(data))
+			writeFile(t, filepath.Join(ws, "build", "sub", "a.txt"), "a")
+
+			paths := []string{"build"}
+
+			err := client.Save(context.Background(), paths, "build-linux-abc")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = client.Save(context.Background(), paths, "build-linux-abc")
+			if !errors.Is(err, ErrCacheExists) {
+				t.Errorf("got %v, want ErrCacheExists", err)
+			}
+
+			err = os.RemoveAll(filepath.Join(ws, "build"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			key, err := client.Restore(context.Background(), paths, "build-linux-def", "build-linux-", "build-")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if key != "build-linux-abc" {
+				t.Errorf("got key %q, want build-linux-abc", key)
+			}
+
+			content, err := os.ReadFile(filepath.Join(ws, "build", "data.bin"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(content) != string(data) {
+				t.Error("the restored content is different")
+			}
+
+			if keys := server.Keys(); len(keys) != 1 {
+				t.Errorf("got %v, want 1 cache", keys)
+			}
+		})
+	}
+}
+
+func TestClient_Lookup(t *testing.T) {
+	for _, service := range services {
+		t.Run(service, func(t *testing.T) {
+			client, _ := newTestClient(t, service)
+
+			ws := os.Getenv(ghactions.GithubWorkspace)
+
+			writeFile(t, filepath.Join(ws, "a.txt"), "a")
+
+			paths := []string{"a.txt"}
+
+			for _, key := range []string{"deps-1", "deps-2", "deps-prefix"} {
+				err := client.Save(context.Background(), paths, key)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			testCases := []struct {
+				desc        string
+				paths       []string
+				key         string
+				restoreKeys []string
+				expected    string
+			}{
+				{
+					desc:     "exact match",
+					paths:    paths,
+					key:      "deps-1",
+					expected: "deps-1",
+				},
+				{
+					desc:        "exact match before prefix",
+					paths:       paths,
+					key:         "deps-2",
+					restoreKeys: []string{"deps-"},
+					expected:    "deps-2",
+				},
+				{
+					desc:        "newest prefix match",
+					paths:       paths,
+					key:         "deps-3",
+					restoreKeys: []string{"deps-"},
+					expected:    "deps-prefix",
+				},
+				{
+					desc:        "restore keys in order",
+					paths:       paths,
+					key:         "deps-3",
+					restoreKeys: []string{"deps-1", "deps-"},
+					expected:    "deps-1",
+				},
+				{
+					desc:        "miss",
+					paths:       paths,
+					key:         "other",
+					restoreKeys: []string{"other-"},
+				},
+				{
+					desc:  "other paths",
+					paths: []string{"b.txt"},
+					key:   "deps-1",
+				},
+			}
+
+			for _, test := range testCases {
+				t.Run(test.desc, func(t *testing.T) {
+					entry, err := client.Lookup(context.Background(), test.paths, test.key, test.restoreKeys...)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					var key string
+					if entry != nil {
+						key = entry.Key
+					}
+
+					if key != test.expected {
+						t.Errorf("got %q, want %q", key, test.expected)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestClient_invalidToken(t *testing.T) {
+	server := cachetest.NewServer()
+	t.Cleanup(server.Close)
+
+	client := NewClientWithURL(server.URL, "invalid")
+
+	_, err := client.Lookup(context.Background(), []string{"a"}, "key")
+	if err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		env        map[string]string
+		expected   string
+		legacy     bool
+		invalidEnv bool
+	}{
+		{
+			desc:     "v2",
+			env:      map[string]string{EnvCacheServiceV2: "true", EnvResultsURL: "https://results.example.com", EnvCacheURL: "https://cache.example.com"},
+			expected: "https://results.example.com/",
+		},
+		{
+			desc:     "legacy",
+			env:      map[string]string{EnvResultsURL: "https://results.example.com", EnvCacheURL: "https://cache.example.com"},
+			expected: "https://cache.example.com/",
+			legacy:   true,
+		},
+		{
+			desc:       "v2 without results URL",
+			env:        map[string]string{EnvCacheServiceV2: "true", EnvCacheURL: "https://cache.example.com"},
+			invalidEnv: true,
+		},
+		{
+			desc:       "legacy without cache URL",
+			env:        map[string]string{EnvResultsURL: "https://results.example.com"},
+			invalidEnv: true,
+		},
+		{
+			desc:       "without token",
+			env:        map[string]string{EnvRuntimeToken: "", EnvCacheURL: "https://cache.example.com"},
+			invalidEnv: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			for _, name := range []string{EnvCacheServiceV2, EnvResultsURL, EnvCacheURL} {
+				t.Setenv(name, "")
+			}
+
+			t.Setenv(EnvRuntimeToken, "token")
+
+			for name, value := range test.env {
+				t.Setenv(name, value)
+			}
+
+			client, err := NewClient()
+			if test.invalidEnv {
+				if err == nil {
+					t.Error("expected an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if client.baseURL != test.expected || client.legacy != test.legacy {
+				t.Errorf("got %s (legacy: %v), want %s (legacy: %v)", client.baseURL, client.legacy, test.expected, test.legacy)
+			}
+		})
+	}
+}
+
+// TestClient_parallel_canceled checks that the running calls are awaited when the context is canceled.
+func TestClient_parallel_canceled(t *testing.T) {
+	client := NewClientWithURL("http://localhost", "token", WithChunkSize(1), WithConcurrency(2))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var running atomic.Int32
+
+	err := client.parallel(ctx, 10, func(ctx context.Context, _, _ int64) error {
+		running.Add(1)
+		defer running.Add(-1)
+
+		cancel()
+		<-ctx.Done()
+
+		time.Sleep(10 * time.Millisecond)
+
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want %v", err, context.Canceled)
+	}
+
+	if n := running.Load(); n != 0 {
+		t.Errorf("got %d running calls after the return", n)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(path, []byte(content), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
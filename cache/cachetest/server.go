@@ -0,0 +1,469 @@
+// Package cachetest A local stand-in of the cache service of the runner (v2 with Twirp, and the legacy service),
+// to test the cache client offline.
+package cachetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Token The runtime token expected by the server.
+const Token = "cachetest-token"
+
+const servicePath = "/twirp/github.actions.results.api.v1.CacheService/"
+
+// legacyPath the path of the legacy service (v1).
+const legacyPath = "/_apis/artifactcache/"
+
+type cacheEntry struct {
+	id        int64
+	key       string
+	version   string
+	blocks    map[string][]byte
+	data      []byte
+	committed bool
+	created   time.Time
+}
+
+// Server A fake cache service.
+type Server struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	nextID int64
+	caches []*cacheEntry
+}
+
+// NewServer Starts a fake cache service, the caller must call Close.
+func NewServer() *Server {
+	s := &Server{nextID: 1}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+servicePath+"GetCacheEntryDownloadURL", s.authorized(s.lookup))
+	mux.HandleFunc("POST "+servicePath+"CreateCacheEntry", s.authorized(s.create))
+	mux.HandleFunc("POST "+servicePath+"FinalizeCacheEntryUpload", s.authorized(s.finalize))
+	mux.HandleFunc("PUT /blobs/{id}", s.putBlob)
+	mux.HandleFunc("GET /blobs/{id}", s.getBlob)
+	mux.HandleFunc("HEAD /blobs/{id}", s.getBlob)
+
+	mux.HandleFunc("GET "+legacyPath+"cache", s.authorized(s.lookupV1))
+	mux.HandleFunc("POST "+legacyPath+"caches", s.authorized(s.reserveV1))
+	mux.HandleFunc("PATCH "+legacyPath+"caches/{id}", s.authorized(s.uploadV1))
+	mux.HandleFunc("POST "+legacyPath+"caches/{id}", s.authorized(s.commitV1))
+
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// Keys Returns the keys of the committed caches.
+func (s *Server) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var keys []string
+
+	for _, c := range s.caches {
+		if c.committed {
+			keys = append(keys, c.key)
+		}
+	}
+
+	return keys
+}
+
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer "+Token {
+			twirpError(rw, http.StatusUnauthorized, "unauthenticated", "invalid token")
+			return
+		}
+
+		next(rw, req)
+	}
+}
+
+// lookup matches the key exactly, then the restore keys by prefix (the newest cache first), like the service.
+func (s *Server) lookup(rw http.ResponseWriter, req *http.Request) {
+	var body struct {
+		Key         string   `json:"key"`
+		RestoreKeys []string `json:"restore_keys"`
+		Version     string   `json:"version"`
+	}
+
+	err := json.NewDecoder(req.Body).Decode(&body)
+	if err != nil || body.Key == "" || body.Version == "" {
+		twirpError(rw, http.StatusBadRequest, "invalid_argument", "invalid request")
+		return
+	}
+
+	found := s.find(body.Version, append([]string{body.Key}, body.RestoreKeys...))
+	if found == nil {
+		writeJSON(rw, map[string]any{"ok": false})
+		return
+	}
+
+	writeJSON(rw, map[string]any{
+		"ok":                  true,
+		"signed_download_url": fmt.Sprintf("%s/blobs/%d?sig=download", s.URL, found.id),
+		"matched_key":         found.key,
+	})
+}
+
+// find matches the first key exactly, then the other keys by prefix (the newest cache first).
+func (s *Server) find(version string, keys []string) *cacheEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	candidates := slices.DeleteFunc(slices.Clone(s.caches), func(c *cacheEntry) bool {
+		return !c.committed || c.version != version
+	})
+
+	// the newest first.
+	slices.Reverse(candidates)
+
+	match := func(fn func(c *cacheEntry) bool) *cacheEntry {
+		idx := slices.IndexFunc(candidates, fn)
+		if idx < 0 {
+			return nil
+		}
+
+		return candidates[idx]
+	}
+
+	found := match(func(c *cacheEntry) bool { return c.key == keys[0] })
+
+	for _, key := range keys[1:] {
+		if found != nil {
+			break
+		}
+
+		found = match(func(c *cacheEntry) bool { return strings.HasPrefix(c.key, key) })
+	}
+
+	return found
+}
+
+func (s *Server) create(rw http.ResponseWriter, req *http.Request) {
+	var body struct {
+		Key     string `json:"key"`
+		Version string `json:"version"`
+	}
+
+	err := json.NewDecoder(req.Body).Decode(&body)
+	if err != nil || body.Key == "" || body.Version == "" {
+		twirpError(rw, http.StatusBadRequest, "invalid_argument", "invalid request")
+		return
+	}
+
+	entry := s.reserve(body.Key, body.Version)
+	if entry == nil {
+		twirpError(rw, http.StatusConflict, "already_exists", "cache entry with the same key and version already exists")
+		return
+	}
+
+	writeJSON(rw, map[string]any{"ok": true, "signed_upload_url": fmt.Sprintf("%s/blobs/%d?sig=upload", s.URL, entry.id)})
+}
+
+func (s *Server) finalize(rw http.ResponseWriter, req *http.Request) {
+	var body struct {
+		Key       string `json:"key"`
+		Version   string `json:"version"`
+		SizeBytes string `json:"size_bytes"`
+	}
+
+	err := json.NewDecoder(req.Body).Decode(&body)
+	if err != nil {
+		twirpError(rw, http.StatusBadRequest, "invalid_argument", "invalid request")
+		return
+	}
+
+	size, err := strconv.ParseInt(body.SizeBytes, 10, 64)
+	if err != nil {
+		twirpError(rw, http.StatusBadRequest, "invalid_argument", "invalid size")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := slices.IndexFunc(s.caches, func(c *cacheEntry) bool { return c.key == body.Key && c.version == body.Version })
+	if idx < 0 || s.caches[idx].committed {
+		twirpError(rw, http.StatusNotFound, "not_found", "cache entry not found")
+		return
+	}
+
+	entry := s.caches[idx]
+
+	if size != int64(len(entry.data)) {
+		twirpError(rw, http.StatusBadRequest, "invalid_argument", fmt.Sprintf("size mismatch: got %d, uploaded %d", size, len(entry.data)))
+		return
+	}
+
+	entry.committed = true
+	entry.created = time.Now()
+
+	writeJSON(rw, map[string]any{"ok": true, "entry_id": strconv.FormatInt(entry.id, 10)})
+}
+
+// reserve creates a cache entry, or returns nil when the key and the version already exist.
+func (s *Server) reserve(key, version string) *cacheEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if slices.ContainsFunc(s.caches, func(c *cacheEntry) bool { return c.key == key && c.version == version }) {
+		return nil
+	}
+
+	entry := &cacheEntry{id: s.nextID, key: key, version: version, blocks: map[string][]byte{}}
+	s.nextID++
+
+	s.caches = append(s.caches, entry)
+
+	return entry
+}
+
+func (s *Server) lookupV1(rw http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+
+	keys := strings.Split(query.Get("keys"), ",")
+	if keys[0] == "" || query.Get("version") == "" {
+		http.Error(rw, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	found := s.find(query.Get("version"), keys)
+	if found == nil {
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	writeJSON(rw, map[string]any{
+		"cacheKey":        found.key,
+		"cacheVersion":    found.version,
+		"archiveLocation": fmt.Sprintf("%s/blobs/%d?sig=download", s.URL, found.id),
+	})
+}
+
+func (s *Server) reserveV1(rw http.ResponseWriter, req *http.Request) {
+	var body struct {
+		Key     string `json:"key"`
+		Version string `json:"version"`
+	}
+
+	err := json.NewDecoder(req.Body).Decode(&body)
+	if err != nil || body.Key == "" || body.Version == "" {
+		http.Error(rw, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	entry := s.reserve(body.Key, body.Version)
+	if entry == nil {
+		http.Error(rw, "cache already exists", http.StatusConflict)
+		return
+	}
+
+	writeJSON(rw, map[string]any{"cacheId": entry.id})
+}
+
+// uploadV1 stores a chunk (Content-Range: bytes <start>-<end>/*).
+func (s *Server) uploadV1(rw http.ResponseWriter, req *http.Request) {
+	var start, end int64
+
+	_, err := fmt.Sscanf(req.Header.Get("Content-Range"), "bytes %d-%d/*", &start, &end)
+	if err != nil {
+		http.Error(rw, "invalid Content-Range", http.StatusBadRequest)
+		return
+	}
+
+	content, err := io.ReadAll(req.Body)
+	if err != nil || int64(len(content)) != end-start+1 {
+		http.Error(rw, "invalid chunk", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.byID(req.PathValue("id"))
+	if entry == nil || entry.committed {
+		http.NotFound(rw, req)
+		return
+	}
+
+	entry.blocks[strconv.FormatInt(start, 10)] = content
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// commitV1 assembles the chunks, they must cover the size without gap.
+func (s *Server) commitV1(rw http.ResponseWriter, req *http.Request) {
+	var body struct {
+		Size int64 `json:"size"`
+	}
+
+	err := json.NewDecoder(req.Body).Decode(&body)
+	if err != nil {
+		http.Error(rw, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.byID(req.PathValue("id"))
+	if entry == nil || entry.committed {
+		http.NotFound(rw, req)
+		return
+	}
+
+	var data []byte
+
+	for int64(len(data)) < body.Size {
+		chunk, ok := entry.blocks[strconv.Itoa(len(data))]
+		if !ok {
+			http.Error(rw, fmt.Sprintf("missing chunk at %d", len(data)), http.StatusBadRequest)
+			return
+		}
+
+		data = append(data, chunk...)
+	}
+
+	if int64(len(data)) != body.Size {
+		http.Error(rw, fmt.Sprintf("size mismatch: got %d, uploaded %d", body.Size, len(data)), http.StatusBadRequest)
+		return
+	}
+
+	entry.data = data
+	entry.committed = true
+	entry.created = time.Now()
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// putBlob handles the blocks (Put Block) and the list of the blocks (Put Block List) of Azure Blob Storage.
+func (s *Server) putBlob(rw http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+
+	if query.Get("sig") != "upload" {
+		http.Error(rw, "invalid signature", http.StatusForbidden)
+		return
+	}
+
+	if req.Header.Get("Authorization") != "" {
+		http.Error(rw, "unexpected Authorization header", http.StatusBadRequest)
+		return
+	}
+
+	content, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.byID(req.PathValue("id"))
+	if entry == nil || entry.committed {
+		http.NotFound(rw, req)
+		return
+	}
+
+	switch query.Get("comp") {
+	case "block":
+		if query.Get("blockid") == "" {
+			http.Error(rw, "missing block ID", http.StatusBadRequest)
+			return
+		}
+
+		entry.blocks[query.Get("blockid")] = content
+
+	case "blocklist":
+		var list struct {
+			Latest []string `xml:"Latest"`
+		}
+
+		err = xml.Unmarshal(content, &list)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var data []byte
+
+		for _, id := range list.Latest {
+			block, ok := entry.blocks[id]
+			if !ok {
+				http.Error(rw, "unknown block "+id, http.StatusBadRequest)
+				return
+			}
+
+			data = append(data, block...)
+		}
+
+		entry.data = data
+
+	default:
+		http.Error(rw, "unsupported operation", http.StatusBadRequest)
+		return
+	}
+
+	rw.WriteHeader(http.StatusCreated)
+}
+
+// getBlob serves the archives (the ranges are supported), without authentication like the signed URLs.
+func (s *Server) getBlob(rw http.ResponseWriter, req *http.Request) {
+	if req.URL.Query().Get("sig") != "download" {
+		http.Error(rw, "invalid signature", http.StatusForbidden)
+		return
+	}
+
+	s.mu.Lock()
+	entry := s.byID(req.PathValue("id"))
+	s.mu.Unlock()
+
+	if entry == nil || !entry.committed {
+		http.NotFound(rw, req)
+		return
+	}
+
+	http.ServeContent(rw, req, "", entry.created, bytes.NewReader(entry.data))
+}
+
+func (s *Server) byID(rawID string) *cacheEntry {
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	idx := slices.IndexFunc(s.caches, func(c *cacheEntry) bool { return c.id == id })
+	if idx < 0 {
+		return nil
+	}
+
+	return s.caches[idx]
+}
+
+func twirpError(rw http.ResponseWriter, status int, code, msg string) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+
+	_ = json.NewEncoder(rw).Encode(map[string]string{"code": code, "msg": msg})
+}
+
+func writeJSON(rw http.ResponseWriter, value any) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	_ = json.NewEncoder(rw).Encode(value)
+}
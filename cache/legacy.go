@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// legacyPath the path of the legacy service (v1), relative to ACTIONS_CACHE_URL.
+const legacyPath = "_apis/artifactcache/"
+
+// lookupV1 finds a cache entry with the legacy service.
+func (c *Client) lookupV1(ctx context.Context, version, key string, restoreKeys []string) (*Entry, error) {
+	query := url.Values{}
+	query.Set("keys", strings.Join(append([]string{key}, restoreKeys...), ","))
+	query.Set("version", version)
+
+	resp, err := c.do(ctx, http.MethodGet, "cache?"+query.Encode(), nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("lookup the cache: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusNoContent, http.StatusNotFound:
+		return nil, nil //nolint:nilnil // cache miss.
+	case http.StatusOK:
+	default:
+		return nil, statusError("lookup the cache", resp)
+	}
+
+	var entry struct {
+		CacheKey        string `json:"cacheKey"`
+		ArchiveLocation string `json:"archiveLocation"`
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&entry)
+	if err != nil {
+		return nil, fmt.Errorf("lookup the cache: %w", err)
+	}
+
+	if entry.ArchiveLocation == "" {
+		return nil, nil //nolint:nilnil // cache miss.
+	}
+
+	return &Entry{Key: entry.CacheKey, Version: version, ArchiveLocation: entry.ArchiveLocation}, nil
+}
+
+// saveV1 reserves the cache, uploads the chunks of the archive in parallel, and commits the cache with the legacy service.
+func (c *Client) saveV1(ctx context.Context, key, version string, archive io.ReaderAt, size int64) error {
+	id, err := c.reserveV1(ctx, key, version, size)
+	if err != nil {
+		return err
+	}
+
+	err = c.parallel(ctx, size, func(ctx context.Context, start, end int64) error {
+		headers := map[string]string{
+			"Content-Type":  "application/octet-stream",
+			"Content-Range": fmt.Sprintf("bytes %d-%d/*", start, end),
+		}
+
+		return c.send(ctx, http.MethodPatch, fmt.Sprintf("caches/%d", id), io.NewSectionReader(archive, start, end-start+1), headers)
+	})
+	if err != nil {
+		return fmt.Errorf("upload a chunk of the cache: %w", err)
+	}
+
+	body, err := json.Marshal(map[string]any{"size": size})
+	if err != nil {
+		return err
+	}
+
+	err = c.send(ctx, http.MethodPost, fmt.Sprintf("caches/%d", id), bytes.NewReader(body), map[string]string{"Content-Type": "application/json"})
+	if err != nil {
+		return fmt.Errorf("commit the cache %q: %w", key, err)
+	}
+
+	return nil
+}
+
+// reserveV1 creates the cache entry, and returns its ID.
+func (c *Client) reserveV1(ctx context.Context, key, version string, size int64) (int64, error) {
+	body, err := json.Marshal(map[string]any{"key": key, "version": version, "cacheSize": size})
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.do(ctx, http.MethodPost, "caches", bytes.NewReader(body), map[string]string{"Content-Type": "application/json"})
+	if err != nil {
+		return 0, fmt.Errorf("reserve the cache %q: %w", key, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusConflict {
+		// another job may be creating this cache.
+		return 0, fmt.Errorf("reserve the cache %q: %w", key, ErrCacheExists)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return 0, statusError("reserve the cache", resp)
+	}
+
+	var reservation struct {
+		CacheID int64 `json:"cacheId"`
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&reservation)
+	if err != nil {
+		return 0, fmt.Errorf("reserve the cache %q: %w", key, err)
+	}
+
+	return reservation.CacheID, nil
+}
+
+// send sends a request to the legacy service, the error statuses are errors.
+func (c *Client) send(ctx context.Context, method, path string, body io.Reader, headers map[string]string) error {
+	resp, err := c.do(ctx, method, path, body, headers)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return statusError(method+" "+path, resp)
+	}
+
+	return nil
+}
+
+// do sends a request to the legacy service.
+func (c *Client) do(ctx context.Context, method, path string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	if body == nil {
+		body = http.NoBody
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+legacyPath+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json;api-version=6.0-preview.1")
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+
+	return resp, nil
+}
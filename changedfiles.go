@@ -0,0 +1,326 @@
+package ghactions
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v71/github"
+)
+
+// API limits.
+const (
+	// maxCompareFiles the maximum number of files returned by the compare API.
+	maxCompareFiles = 300
+	// maxPullRequestFiles the maximum number of files returned by the pull request files API.
+	maxPullRequestFiles = 3000
+)
+
+// zeroSHA the "before" SHA of a push event that creates a branch.
+const zeroSHA = "0000000000000000000000000000000000000000"
+
+// errAPILimit the API cannot return all the files.
+var errAPILimit = errors.New("too many changed files for the API")
+
+// ChangedFile A file changed by an event.
+type ChangedFile struct {
+	Filename string
+	// PreviousFilename the previous name of a renamed file.
+	PreviousFilename string
+	// Status added, removed, modified, renamed, copied, changed, or unchanged.
+	Status    string
+	Additions int
+	Deletions int
+}
+
+// ChangedFilesOption An option of ChangedFiles.
+type ChangedFilesOption func(*changedFilesConfig)
+
+type changedFilesConfig struct {
+	paths       Filter
+	pathsIgnore Filter
+	gitFallback bool
+}
+
+// WithPaths Keeps only the files matching the patterns (same semantics as the `paths` of a workflow).
+func WithPaths(patterns ...string) ChangedFilesOption {
+	return func(cfg *changedFilesConfig) {
+		cfg.paths = append(cfg.paths, patterns...)
+	}
+}
+
+// WithPathsIgnore Removes the files matching the patterns (same semantics as the `paths-ignore` of a workflow).
+func WithPathsIgnore(patterns ...string) ChangedFilesOption {
+	return func(cfg *changedFilesConfig) {
+		cfg.pathsIgnore = append(cfg.pathsIgnore, patterns...)
+	}
+}
+
+// WithoutGitFallback Disables the fallback on `git diff` (in GITHUB_WORKSPACE) when the API limits are hit.
+func WithoutGitFallback() ChangedFilesOption {
+	return func(cfg *changedFilesConfig) {
+		cfg.gitFallback = false
+	}
+}
+
+// ChangedFiles Returns the files changed by an event (push, pull_request, pull_request_target),
+// the event is the event given to the handler (Run or Handle).
+//   - push: the commits are compared with the compare API.
+//   - pull_request: the files of the pull request are listed.
+//
+// When the API limits are hit, the files are computed with `git diff base...head` in GITHUB_WORKSPACE
+// (the commits must have been fetched).
+func (a *Action) ChangedFiles(ctx context.Context, event any, opts ...ChangedFilesOption) ([]ChangedFile, error) {
+	cfg := &changedFilesConfig{gitFallback: true}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	repo, err := RepoFromEvent(event)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := a.clientFor(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	var base, head string
+	var files []ChangedFile
+
+	switch evt := event.(type) {
+	case *github.PushEvent:
+		base, head = evt.GetBefore(), evt.GetAfter()
+		files, err = pushChangedFiles(ctx, client, repo, evt)
+
+	case *github.PullRequestEvent:
+		base, head = evt.GetPullRequest().GetBase().GetSHA(), evt.GetPullRequest().GetHead().GetSHA()
+		files, err = pullRequestChangedFiles(ctx, client, repo, evt.GetPullRequest())
+
+	case *github.PullRequestTargetEvent:
+		base, head = evt.GetPullRequest().GetBase().GetSHA(), evt.GetPullRequest().GetHead().GetSHA()
+		files, err = pullRequestChangedFiles(ctx, client, repo, evt.GetPullRequest())
+
+	default:
+		return nil, fmt.Errorf("changed files: unsupported event %T", event)
+	}
+
+	if errors.Is(err, errAPILimit) && cfg.gitFallback {
+		files, err = gitChangedFiles(ctx, os.Getenv(GithubWorkspace), base, head)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg.filter(files), nil
+}
+
+func (cfg *changedFilesConfig) filter(files []ChangedFile) []ChangedFile {
+	var result []ChangedFile
+
+	for _, file := range files {
+		if cfg.paths.Includes(file.Filename) && !cfg.pathsIgnore.Excludes(file.Filename) {
+			result = append(result, file)
+		}
+	}
+
+	return result
+}
+
+func pushChangedFiles(ctx context.Context, client *github.Client, repo Repo, evt *github.PushEvent) ([]ChangedFile, error) {
+	if evt.GetBefore() == "" || evt.GetBefore() == zeroSHA || evt.GetDeleted() {
+		// new (or deleted) branch: no base to compare with.
+		return pushPayloadFiles(evt), nil
+	}
+
+	comparison, _, err := client.Repositories.CompareCommits(ctx, repo.Owner, repo.Name, evt.GetBefore(), evt.GetAfter(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("compare %s...%s: %w", evt.GetBefore(), evt.GetAfter(), err)
+	}
+
+	if len(comparison.Files) >= maxCompareFiles {
+		return nil, errAPILimit
+	}
+
+	return toChangedFiles(comparison.Files), nil
+}
+
+func pullRequestChangedFiles(ctx context.Context, client *github.Client, repo Repo, pr *github.PullRequest) ([]ChangedFile, error) {
+	if pr.GetChangedFiles() > maxPullRequestFiles {
+		return nil, errAPILimit
+	}
+
+	var files []*github.CommitFile
+
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		page, resp, err := client.PullRequests.ListFiles(ctx, repo.Owner, repo.Name, pr.GetNumber(), opts)
+		if err != nil {
+			return nil, fmt.Errorf("list files of the pull request #%d: %w", pr.GetNumber(), err)
+		}
+
+		files = append(files, page...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	if len(files) >= maxPullRequestFiles {
+		return nil, errAPILimit
+	}
+
+	return toChangedFiles(files), nil
+}
+
+func toChangedFiles(files []*github.CommitFile) []ChangedFile {
+	result := make([]ChangedFile, 0, len(files))
+
+	for _, file := range files {
+		result = append(result, ChangedFile{
+			Filename:         file.GetFilename(),
+			PreviousFilename: file.GetPreviousFilename(),
+			Status:           file.GetStatus(),
+			Additions:        file.GetAdditions(),
+			Deletions:        file.GetDeletions(),
+		})
+	}
+
+	return result
+}
+
+// pushPayloadFiles uses the commits of the payload (no additions and deletions).
+func pushPayloadFiles(evt *github.PushEvent) []ChangedFile {
+	status := map[string]string{}
+	seen := map[string]bool{}
+
+	var order []string
+
+	set := func(filename, value string) {
+		if !seen[filename] {
+			seen[filename] = true
+			order = append(order, filename)
+		}
+
+		switch {
+		case status[filename] == "added" && value == "modified":
+			// still added.
+		case status[filename] == "added" && value == "removed":
+			delete(status, filename)
+			return
+		default:
+			status[filename] = value
+		}
+	}
+
+	for _, commit := range evt.Commits {
+		for _, f := range commit.Added {
+			set(f, "added")
+		}
+
+		for _, f := range commit.Modified {
+			set(f, "modified")
+		}
+
+		for _, f := range commit.Removed {
+			set(f, "removed")
+		}
+	}
+
+	var result []ChangedFile
+
+	for _, filename := range order {
+		if s, ok := status[filename]; ok {
+			result = append(result, ChangedFile{Filename: filename, Status: s})
+		}
+	}
+
+	return result
+}
+
+// gitChangedFiles computes the changed files with `git diff base...head`:
+// the changes of head since the merge base (like the compare API), not the difference between base and head.
+func gitChangedFiles(ctx context.Context, dir, base, head string) ([]ChangedFile, error) {
+	if dir == "" {
+		dir = "."
+	}
+
+	if base == "" || base == zeroSHA || head == "" {
+		return nil, errors.New("git diff: missing base or head commit")
+	}
+
+	run := func(args ...string) ([]byte, error) {
+		cmd := exec.CommandContext(ctx, "git", append([]string{"-C", filepath.Clean(dir), "diff"}, args...)...)
+
+		stderr := &bytes.Buffer{}
+		cmd.Stderr = stderr
+
+		output, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("git diff %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+		}
+
+		return output, nil
+	}
+
+	revisions := base + "..." + head
+
+	nameStatus, err := run("--name-status", "--no-renames", "-z", revisions)
+	if err != nil {
+		return nil, err
+	}
+
+	numStat, err := run("--numstat", "--no-renames", "-z", revisions)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := map[string][2]int{}
+
+	for _, line := range strings.Split(string(numStat), "\x00") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+
+		// binary files: "-".
+		additions, _ := strconv.Atoi(fields[0])
+		deletions, _ := strconv.Atoi(fields[1])
+
+		stats[fields[2]] = [2]int{additions, deletions}
+	}
+
+	statuses := map[string]string{"A": "added", "D": "removed", "M": "modified", "T": "changed"}
+
+	var files []ChangedFile
+
+	parts := strings.Split(strings.TrimSuffix(string(nameStatus), "\x00"), "\x00")
+	for i := 0; i+1 < len(parts); i += 2 {
+		status, ok := statuses[parts[i]]
+		if !ok {
+			status = "modified"
+		}
+
+		filename := parts[i+1]
+
+		files = append(files, ChangedFile{
+			Filename:  filename,
+			Status:    status,
+			Additions: stats[filename][0],
+			Deletions: stats[filename][1],
+		})
+	}
+
+	return files, nil
+}
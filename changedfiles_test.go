@@ -0,0 +1,199 @@
+package ghactions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v71/github"
+)
+
+// newTestAction creates an Action with a client using a fake GitHub API.
+func newTestAction(t *testing.T, mux *http.ServeMux) *Action {
+	t.Helper()
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	action := NewAction(context.Background())
+	action.client = client
+	action.Logger = discardLogger()
+
+	return action
+}
+
+// writeEvent writes an event payload and sets the environment variables of the event.
+func writeEvent(t *testing.T, eventName, payload string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "event.json")
+
+	err := os.WriteFile(path, []byte(payload), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(GithubEventName, eventName)
+	t.Setenv(GithubEventPath, path)
+}
+
+func TestAction_ChangedFiles_pullRequest(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/repos/ldez/ghactions/pulls/7/files", func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+
+		if req.URL.Query().Get("page") == "2" {
+			_, _ = rw.Write([]byte(`[{"filename":"docs/readme.md","status":"modified","additions":1,"deletions":1}]`))
+			return
+		}
+
+		rw.Header().Set("Link", fmt.Sprintf(`<%s?page=2>; rel="next"`, req.URL.Path))
+		_, _ = rw.Write([]byte(`[{"filename":"actions.go","status":"modified","additions":10,"deletions":2},` +
+			`{"filename":"vendor/lib.go","status":"added","additions":5}]`))
+	})
+
+	action := newTestAction(t, mux)
+
+	var files []ChangedFile
+
+	action.OnPullRequest(func(_ *github.Client, event *github.PullRequestEvent) error {
+		var err error
+		files, err = action.ChangedFiles(context.Background(), event, WithPaths("**.go", "**.md"), WithPathsIgnore("vendor/**"))
+
+		return err
+	})
+
+	// dispatched with Handle (webhook): GITHUB_EVENT_PATH is not used.
+	payload := `{"number":7,"pull_request":{"number":7,"changed_files":3},"repository":{"full_name":"ldez/ghactions"}}`
+
+	err := action.Handle(context.Background(), "pull_request", []byte(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ChangedFile{
+		{Filename: "actions.go", Status: "modified", Additions: 10, Deletions: 2},
+		{Filename: "docs/readme.md", Status: "modified", Additions: 1, Deletions: 1},
+	}
+
+	if fmt.Sprint(files) != fmt.Sprint(expected) {
+		t.Errorf("got %v, want %v", files, expected)
+	}
+}
+
+func TestAction_ChangedFiles_pushNewBranch(t *testing.T) {
+	action := newTestAction(t, http.NewServeMux())
+
+	event := &github.PushEvent{}
+	unmarshal(t, `{
+  "before": "0000000000000000000000000000000000000000",
+  "after": "b61d5b415960c13b8ffcdf450e3249a831d9016a",
+  "commits": [
+    {"added": ["a.go", "b.go"], "modified": [], "removed": []},
+    {"added": [], "modified": ["a.go"], "removed": ["b.go", "c.go"]}
+  ],
+  "repository": {"full_name": "ldez/ghactions"}
+}`, event)
+
+	files, err := action.ChangedFiles(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ChangedFile{
+		{Filename: "a.go", Status: "added"},
+		{Filename: "c.go", Status: "removed"},
+	}
+
+	if fmt.Sprint(files) != fmt.Sprint(expected) {
+		t.Errorf("got %v, want %v", files, expected)
+	}
+}
+
+func TestAction_ChangedFiles_gitFallback(t *testing.T) {
+	dir := t.TempDir()
+
+	git := func(args ...string) string {
+		t.Helper()
+
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+
+		output, err := cmd.Output()
+		if err != nil {
+			t.Skipf("git %s: %v", strings.Join(args, " "), err)
+		}
+
+		return strings.TrimSpace(string(output))
+	}
+
+	git("init", "-q")
+	writeFile(t, filepath.Join(dir, "a.txt"), "a\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "first")
+
+	git("checkout", "-q", "-b", "feature")
+	writeFile(t, filepath.Join(dir, "a.txt"), "a\nb\n")
+	writeFile(t, filepath.Join(dir, "c.txt"), "c\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "second")
+	head := git("rev-parse", "HEAD")
+
+	// the base branch has moved since the creation of the feature branch.
+	git("checkout", "-q", "-")
+	writeFile(t, filepath.Join(dir, "d.txt"), "d\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "third")
+	base := git("rev-parse", "HEAD")
+
+	action := newTestAction(t, http.NewServeMux())
+
+	t.Setenv(GithubWorkspace, dir)
+
+	event := &github.PullRequestEvent{}
+	unmarshal(t, fmt.Sprintf(`{"number":7,"pull_request":{"number":7,"changed_files":%d,"base":{"sha":%q},"head":{"sha":%q}},"repository":{"full_name":"ldez/ghactions"}}`,
+		maxPullRequestFiles+1, base, head), event)
+
+	files, err := action.ChangedFiles(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ChangedFile{
+		{Filename: "a.txt", Status: "modified", Additions: 1},
+		{Filename: "c.txt", Status: "added", Additions: 1},
+	}
+
+	if fmt.Sprint(files) != fmt.Sprint(expected) {
+		t.Errorf("got %v, want %v", files, expected)
+	}
+}
+
+func unmarshal(t *testing.T, payload string, v any) {
+	t.Helper()
+
+	err := json.Unmarshal([]byte(payload), v)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	err := os.WriteFile(path, []byte(content), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
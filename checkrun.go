@@ -0,0 +1,317 @@
+package ghactions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/google/go-github/v71/github"
+)
+
+// maxAnnotations the maximum number of annotations per request of the check runs API.
+const maxAnnotations = 50
+
+// Check run conclusions.
+const (
+	ConclusionSuccess        = "success"
+	ConclusionFailure        = "failure"
+	ConclusionNeutral        = "neutral"
+	ConclusionCancelled      = "cancelled"
+	ConclusionSkipped        = "skipped"
+	ConclusionTimedOut       = "timed_out"
+	ConclusionActionRequired = "action_required"
+)
+
+// CheckRunOption An option of StartCheckRun and RunCheck.
+type CheckRunOption func(*checkRunConfig)
+
+type checkRunConfig struct {
+	headSHA    string
+	detailsURL string
+	externalID string
+}
+
+// WithHeadSHA Defines the commit of the check run (by default, the head SHA of the event).
+func WithHeadSHA(sha string) CheckRunOption {
+	return func(cfg *checkRunConfig) {
+		cfg.headSHA = sha
+	}
+}
+
+// WithDetailsURL Defines the URL of the details of the check run.
+func WithDetailsURL(url string) CheckRunOption {
+	return func(cfg *checkRunConfig) {
+		cfg.detailsURL = url
+	}
+}
+
+// WithExternalID Defines the external ID of the check run.
+func WithExternalID(id string) CheckRunOption {
+	return func(cfg *checkRunConfig) {
+		cfg.externalID = id
+	}
+}
+
+// CheckRun A check run bound to the head SHA of the event.
+// The annotations are sent by batches of 50 (the limit of the API).
+type CheckRun struct {
+	client *github.Client
+	repo   Repo
+	id     int64
+	name   string
+
+	mu        sync.Mutex
+	title     string
+	summary   string
+	text      string
+	pending   []*github.CheckRunAnnotation
+	concluded bool
+}
+
+// StartCheckRun Creates a check run with the status in_progress.
+// The event is the event given to the handler (Run or Handle), it can be nil outside a handler (GITHUB_REPOSITORY and GITHUB_SHA are used).
+// The head SHA is taken from the event payload (the head of the pull request, the pushed commit, etc.).
+func (a *Action) StartCheckRun(ctx context.Context, event any, name string, opts ...CheckRunOption) (*CheckRun, error) {
+	cfg := &checkRunConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	repo, err := repoFor(event)
+	if err != nil {
+		return nil, fmt.Errorf("check run: %w", err)
+	}
+
+	if cfg.headSHA == "" {
+		cfg.headSHA = headSHA(event)
+		if cfg.headSHA == "" {
+			return nil, fmt.Errorf("check run: unable to find the head SHA of %T (see WithHeadSHA)", event)
+		}
+	}
+
+	client, err := a.clientFor(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	createOpts := github.CreateCheckRunOptions{
+		Name:    name,
+		HeadSHA: cfg.headSHA,
+		Status:  github.Ptr("in_progress"),
+	}
+
+	if cfg.detailsURL != "" {
+		createOpts.DetailsURL = github.Ptr(cfg.detailsURL)
+	}
+
+	if cfg.externalID != "" {
+		createOpts.ExternalID = github.Ptr(cfg.externalID)
+	}
+
+	checkRun, _, err := client.Checks.CreateCheckRun(ctx, repo.Owner, repo.Name, createOpts)
+	if err != nil {
+		return nil, fmt.Errorf("create the check run %q: %w", name, err)
+	}
+
+	return &CheckRun{
+		client: client,
+		repo:   repo,
+		id:     checkRun.GetID(),
+		name:   name,
+		title:  name,
+	}, nil
+}
+
+// RunCheck Runs fn inside a check run, the check run is concluded when fn returns:
+//   - success when fn returns nil.
+//   - failure when fn returns an error or panics (the panic is propagated).
+//
+// fn can conclude the check run itself with CheckRun.Conclude (e.g. neutral).
+func (a *Action) RunCheck(ctx context.Context, event any, name string, fn func(ctx context.Context, run *CheckRun) error, opts ...CheckRunOption) error {
+	run, err := a.StartCheckRun(ctx, event, name, opts...)
+	if err != nil {
+		return err
+	}
+
+	// the check run must be concluded even if the context is canceled.
+	concludeCtx := context.WithoutCancel(ctx)
+
+	defer func() {
+		if r := recover(); r != nil {
+			run.SetSummary("", fmt.Sprintf("panic: %v", r), "")
+			_ = run.Conclude(concludeCtx, ConclusionFailure)
+
+			panic(r)
+		}
+	}()
+
+	err = fn(ctx, run)
+	if err != nil {
+		run.SetSummary("", err.Error(), "")
+
+		return errors.Join(err, run.Conclude(concludeCtx, ConclusionFailure))
+	}
+
+	return run.Conclude(concludeCtx, ConclusionSuccess)
+}
+
+// ID Returns the ID of the check run.
+func (c *CheckRun) ID() int64 {
+	return c.id
+}
+
+// SetSummary Defines the output of the check run (sent with the next update).
+// The empty values are ignored.
+func (c *CheckRun) SetSummary(title, summary, text string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if title != "" {
+		c.title = title
+	}
+
+	if summary != "" {
+		c.summary = summary
+	}
+
+	if text != "" {
+		c.text = text
+	}
+}
+
+// Annotate Adds annotations to the check run.
+// The annotations are sent when a batch of 50 annotations is full, by Flush, and by Conclude.
+func (c *CheckRun) Annotate(ctx context.Context, annotations ...*github.CheckRunAnnotation) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pending = append(c.pending, annotations...)
+
+	for len(c.pending) >= maxAnnotations {
+		err := c.update(ctx, "")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Flush Sends the pending annotations and the output.
+func (c *CheckRun) Flush(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		err := c.update(ctx, "")
+		if err != nil {
+			return err
+		}
+
+		if len(c.pending) == 0 {
+			return nil
+		}
+	}
+}
+
+// Conclude Sends the pending annotations and completes the check run with a conclusion (e.g. ConclusionNeutral).
+// The next calls do nothing.
+func (c *CheckRun) Conclude(ctx context.Context, conclusion string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.concluded {
+		return nil
+	}
+
+	for len(c.pending) > maxAnnotations {
+		err := c.update(ctx, "")
+		if err != nil {
+			return err
+		}
+	}
+
+	err := c.update(ctx, conclusion)
+	if err != nil {
+		return err
+	}
+
+	c.concluded = true
+
+	return nil
+}
+
+// update sends the output with the next batch of annotations.
+// The check run is completed when the conclusion is not empty.
+func (c *CheckRun) update(ctx context.Context, conclusion string) error {
+	batch := c.pending[:min(len(c.pending), maxAnnotations)]
+
+	summary := c.summary
+	if summary == "" {
+		// the summary is required by the API.
+		summary = c.title
+	}
+
+	opts := github.UpdateCheckRunOptions{
+		Name: c.name,
+		Output: &github.CheckRunOutput{
+			Title:       github.Ptr(c.title),
+			Summary:     github.Ptr(summary),
+			Annotations: batch,
+		},
+	}
+
+	if c.text != "" {
+		opts.Output.Text = github.Ptr(c.text)
+	}
+
+	if conclusion != "" {
+		opts.Status = github.Ptr("completed")
+		opts.Conclusion = github.Ptr(conclusion)
+	}
+
+	_, _, err := c.client.Checks.UpdateCheckRun(ctx, c.repo.Owner, c.repo.Name, c.id, opts)
+	if err != nil {
+		return fmt.Errorf("update the check run %q: %w", c.name, err)
+	}
+
+	c.pending = c.pending[len(batch):]
+
+	return nil
+}
+
+// headSHA finds the head commit of an event, GITHUB_SHA is used without event.
+func headSHA(event any) string {
+	if event == nil {
+		return os.Getenv(GithubSha)
+	}
+
+	var sha string
+
+	switch evt := event.(type) {
+	case *github.PullRequestEvent:
+		sha = evt.GetPullRequest().GetHead().GetSHA()
+	case *github.PullRequestTargetEvent:
+		sha = evt.GetPullRequest().GetHead().GetSHA()
+	case *github.PullRequestReviewEvent:
+		sha = evt.GetPullRequest().GetHead().GetSHA()
+	case *github.PullRequestReviewCommentEvent:
+		sha = evt.GetPullRequest().GetHead().GetSHA()
+	case *github.CheckSuiteEvent:
+		sha = evt.GetCheckSuite().GetHeadSHA()
+	case *github.CheckRunEvent:
+		sha = evt.GetCheckRun().GetHeadSHA()
+	case *github.WorkflowRunEvent:
+		sha = evt.GetWorkflowRun().GetHeadSHA()
+	case *github.MergeGroupEvent:
+		sha = evt.GetMergeGroup().GetHeadSHA()
+	case *github.PushEvent:
+		if !evt.GetDeleted() {
+			sha = evt.GetAfter()
+		}
+	}
+
+	return sha
+}
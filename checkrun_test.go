@@ -0,0 +1,214 @@
+package ghactions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v71/github"
+)
+
+// fakeChecks a fake check runs API.
+type fakeChecks struct {
+	mu      sync.Mutex
+	headSHA string
+	updates []github.UpdateCheckRunOptions
+}
+
+func (f *fakeChecks) mux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v3/repos/ldez/ghactions/check-runs", func(rw http.ResponseWriter, req *http.Request) {
+		var opts github.CreateCheckRunOptions
+		_ = json.NewDecoder(req.Body).Decode(&opts)
+
+		f.mu.Lock()
+		f.headSHA = opts.HeadSHA
+		f.mu.Unlock()
+
+		_, _ = rw.Write([]byte(`{"id":42}`))
+	})
+	mux.HandleFunc("PATCH /api/v3/repos/ldez/ghactions/check-runs/42", func(rw http.ResponseWriter, req *http.Request) {
+		var opts github.UpdateCheckRunOptions
+		_ = json.NewDecoder(req.Body).Decode(&opts)
+
+		f.mu.Lock()
+		f.updates = append(f.updates, opts)
+		f.mu.Unlock()
+
+		_, _ = rw.Write([]byte(`{"id":42}`))
+	})
+
+	return mux
+}
+
+func (f *fakeChecks) conclusion() string {
+	if len(f.updates) == 0 {
+		return ""
+	}
+
+	return f.updates[len(f.updates)-1].GetConclusion()
+}
+
+func TestAction_RunCheck(t *testing.T) {
+	checks := &fakeChecks{}
+	action := newTestAction(t, checks.mux())
+
+	event := &github.PullRequestEvent{}
+	unmarshal(t, `{"number":7,"pull_request":{"number":7,"head":{"sha":"abc"}},"repository":{"full_name":"ldez/ghactions"}}`, event)
+
+	t.Setenv(GithubSha, "merge")
+
+	err := action.RunCheck(context.Background(), event, "lint", func(ctx context.Context, run *CheckRun) error {
+		for i := range 120 {
+			err := run.Annotate(ctx, &github.CheckRunAnnotation{
+				Path:            github.Ptr("main.go"),
+				StartLine:       github.Ptr(i + 1),
+				EndLine:         github.Ptr(i + 1),
+				AnnotationLevel: github.Ptr("warning"),
+				Message:         github.Ptr(fmt.Sprintf("issue %d", i)),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		run.SetSummary("Lint", "120 issues", "")
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if checks.headSHA != "abc" {
+		t.Errorf("got head SHA %q, want abc", checks.headSHA)
+	}
+
+	var sizes []int
+	for _, update := range checks.updates {
+		sizes = append(sizes, len(update.Output.Annotations))
+	}
+
+	if fmt.Sprint(sizes) != "[50 50 20]" {
+		t.Errorf("got batches %v, want [50 50 20]", sizes)
+	}
+
+	last := checks.updates[len(checks.updates)-1]
+	if last.GetStatus() != "completed" || last.GetConclusion() != ConclusionSuccess {
+		t.Errorf("got %s/%s, want completed/success", last.GetStatus(), last.GetConclusion())
+	}
+
+	if last.Output.GetTitle() != "Lint" || last.Output.GetSummary() != "120 issues" {
+		t.Errorf("got output %q/%q", last.Output.GetTitle(), last.Output.GetSummary())
+	}
+}
+
+func TestAction_RunCheck_failure(t *testing.T) {
+	event := &github.PushEvent{}
+	unmarshal(t, `{"after":"def","repository":{"full_name":"ldez/ghactions"}}`, event)
+
+	t.Run("error", func(t *testing.T) {
+		checks := &fakeChecks{}
+		action := newTestAction(t, checks.mux())
+
+		errBoom := errors.New("boom")
+
+		err := action.RunCheck(context.Background(), event, "test", func(context.Context, *CheckRun) error {
+			return errBoom
+		})
+		if !errors.Is(err, errBoom) {
+			t.Fatalf("got %v, want %v", err, errBoom)
+		}
+
+		if checks.conclusion() != ConclusionFailure {
+			t.Errorf("got conclusion %q, want failure", checks.conclusion())
+		}
+
+		if checks.headSHA != "def" {
+			t.Errorf("got head SHA %q, want def", checks.headSHA)
+		}
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		checks := &fakeChecks{}
+		action := newTestAction(t, checks.mux())
+
+		defer func() {
+			if recover() == nil {
+				t.Error("the panic must be propagated")
+			}
+
+			if checks.conclusion() != ConclusionFailure {
+				t.Errorf("got conclusion %q, want failure", checks.conclusion())
+			}
+		}()
+
+		_ = action.RunCheck(context.Background(), event, "test", func(context.Context, *CheckRun) error {
+			panic("boom")
+		})
+	})
+
+	t.Run("explicit conclusion", func(t *testing.T) {
+		checks := &fakeChecks{}
+		action := newTestAction(t, checks.mux())
+
+		err := action.RunCheck(context.Background(), event, "test", func(ctx context.Context, run *CheckRun) error {
+			return run.Conclude(ctx, ConclusionNeutral)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(checks.updates) != 1 || checks.conclusion() != ConclusionNeutral {
+			t.Errorf("got %d updates, conclusion %q, want 1 update, neutral", len(checks.updates), checks.conclusion())
+		}
+	})
+}
+
+func TestAction_StartCheckRun_headSHA(t *testing.T) {
+	t.Setenv(GithubRepository, "ldez/ghactions")
+	t.Setenv(GithubSha, "env")
+
+	testCases := []struct {
+		desc     string
+		event    any
+		expected string
+	}{
+		{
+			desc:     "without event",
+			expected: "env",
+		},
+		{
+			desc:  "event without head",
+			event: &github.IssuesEvent{Repo: &github.Repository{FullName: github.Ptr("ldez/ghactions")}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			checks := &fakeChecks{}
+			action := newTestAction(t, checks.mux())
+
+			_, err := action.StartCheckRun(context.Background(), test.event, "test")
+			if test.expected == "" {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if checks.headSHA != test.expected {
+				t.Errorf("got head SHA %q, want %q", checks.headSHA, test.expected)
+			}
+		})
+	}
+}
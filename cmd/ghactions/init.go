@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/ldez/ghactions"
+	"github.com/ldez/ghactions/internal/events"
+)
+
+const (
+	runnerDocker    = "docker"
+	runnerComposite = "composite"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// project the description of the generated project.
+type project struct {
+	Name   string
+	Module string
+	Runner string
+	Events []handler
+	Inputs []input
+}
+
+// handler an event handled by the action.
+type handler struct {
+	Event  string // webhook name (ex: pull_request).
+	Method string // Action method (ex: OnPullRequest).
+	Type   string // qualified type (ex: github.PullRequestEvent).
+}
+
+// input an input of the action.
+type input struct {
+	Name  string // ex: dry-run.
+	Field string // ex: DryRun.
+	Env   string // ex: INPUT_DRY-RUN.
+}
+
+func runInit(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+
+	dir := fs.String("dir", ".", "Output directory.")
+	name := fs.String("name", "", "Name of the action (default: the name of the output directory).")
+	module := fs.String("module", "", "Go module path (default: the name of the action).")
+	events := fs.String("events", "", "Comma separated list of the events handled by the action (ex: push,pull_request).")
+	inputs := fs.String("inputs", "", "Comma separated list of the inputs of the action (ex: dry-run,label).")
+	runner := fs.String("runner", runnerDocker, "How the action is run: docker (multi-stage Dockerfile) or composite (binary downloaded from the releases).")
+	force := fs.Bool("force", false, "Overwrite the existing files.")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	visited := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { visited[f.Name] = true })
+
+	prompt := newPrompter(stdin, stdout)
+
+	if !visited["events"] {
+		*events = prompt.ask("Events handled by the action (comma separated)", "push")
+	}
+
+	if !visited["inputs"] {
+		*inputs = prompt.ask("Inputs of the action (comma separated)", "")
+	}
+
+	outDir, err := filepath.Abs(*dir)
+	if err != nil {
+		return err
+	}
+
+	if *name == "" {
+		*name = filepath.Base(outDir)
+	}
+
+	if *module == "" {
+		*module = *name
+	}
+
+	p, err := newProject(*name, *module, *runner, splitList(*events), splitList(*inputs))
+	if err != nil {
+		return err
+	}
+
+	files, err := p.render()
+	if err != nil {
+		return err
+	}
+
+	return writeFiles(outDir, files, *force, stdout)
+}
+
+func newProject(name, module, runner string, events, inputs []string) (*project, error) {
+	if runner != runnerDocker && runner != runnerComposite {
+		return nil, fmt.Errorf("unsupported runner: %q", runner)
+	}
+
+	if len(events) == 0 {
+		return nil, errors.New("at least one event is required")
+	}
+
+	handlers := availableHandlers()
+
+	p := &project{Name: name, Module: module, Runner: runner}
+
+	for _, event := range events {
+		h, ok := handlers[event]
+		if !ok {
+			return nil, fmt.Errorf("unsupported event: %q", event)
+		}
+
+		p.Events = append(p.Events, h)
+	}
+
+	for _, in := range inputs {
+		if in == "token" {
+			// always defined.
+			continue
+		}
+
+		p.Inputs = append(p.Inputs, input{
+			Name:  in,
+			Field: toFieldName(in),
+			Env:   "INPUT_" + strings.ToUpper(strings.ReplaceAll(in, " ", "_")),
+		})
+	}
+
+	return p, nil
+}
+
+// render renders all the files of the project: path -> content.
+func (p *project) render() (map[string][]byte, error) {
+	tmpl, err := template.ParseFS(templates, "templates/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]string{
+		"main.go":      "main.go.tmpl",
+		"main_test.go": "main_test.go.tmpl",
+		"go.mod":       "go.mod.tmpl",
+		"action.yml":   "action.yml.tmpl",
+		filepath.Join(".github", "workflows", "example.yml"): "workflow.yml.tmpl",
+	}
+
+	if p.Runner == runnerDocker {
+		files["Dockerfile"] = "Dockerfile.tmpl"
+	}
+
+	result := make(map[string][]byte)
+
+	for path, name := range files {
+		buf := &bytes.Buffer{}
+
+		err = tmpl.ExecuteTemplate(buf, name, p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		content := buf.Bytes()
+
+		if filepath.Ext(path) == ".go" {
+			content, err = format.Source(content)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+		}
+
+		result[path] = content
+	}
+
+	for _, h := range p.Events {
+		result[filepath.Join("fixtures", h.Event+".json")] = []byte("{}\n")
+	}
+
+	return result, nil
+}
+
+func writeFiles(dir string, files map[string][]byte, force bool, stdout io.Writer) error {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+
+	slices.Sort(paths)
+
+	if !force {
+		for _, path := range paths {
+			_, err := os.Stat(filepath.Join(dir, path))
+			if err == nil {
+				return fmt.Errorf("%s already exists (use -force to overwrite)", path)
+			}
+		}
+	}
+
+	for _, path := range paths {
+		target := filepath.Join(dir, path)
+
+		err := os.MkdirAll(filepath.Dir(target), 0o750)
+		if err != nil {
+			return err
+		}
+
+		err = os.WriteFile(target, files[path], 0o600)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(stdout, "created %s\n", path)
+	}
+
+	fmt.Fprintln(stdout, "\nRun 'go mod tidy' to fetch the dependencies.")
+
+	return nil
+}
+
+// availableHandlers finds the handlers of ghactions.Action: event name -> handler.
+func availableHandlers() map[string]handler {
+	methods := map[string]string{}
+
+	actionType := reflect.TypeOf(&ghactions.Action{})
+	for i := range actionType.NumMethod() {
+		method := actionType.Method(i)
+		if !strings.HasPrefix(method.Name, "On") || method.Type.NumIn() < 2 {
+			continue
+		}
+
+		fn := method.Type.In(1)
+		if fn.Kind() != reflect.Func || fn.NumIn() != 2 || fn.In(1).Kind() != reflect.Pointer {
+			continue
+		}
+
+		methods[fn.In(1).Elem().Name()] = method.Name
+	}
+
+	handlers := map[string]handler{}
+
+	for _, event := range github.MessageTypes() {
+		typeName := reflect.TypeOf(github.EventForType(event)).Elem().Name()
+
+		if method, ok := methods[typeName]; ok {
+			handlers[event] = handler{Event: event, Method: method, Type: "github." + typeName}
+		}
+	}
+
+	for event, typeName := range events.Local {
+		if method, ok := methods[typeName]; ok {
+			handlers[event] = handler{Event: event, Method: method, Type: "ghactions." + typeName}
+		}
+	}
+
+	return handlers
+}
+
+type prompter struct {
+	scanner *bufio.Scanner
+	out     io.Writer
+}
+
+func newPrompter(in io.Reader, out io.Writer) *prompter {
+	return &prompter{scanner: bufio.NewScanner(in), out: out}
+}
+
+func (p *prompter) ask(question, defaultValue string) string {
+	if defaultValue != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, defaultValue)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+
+	if !p.scanner.Scan() {
+		fmt.Fprintln(p.out)
+		return defaultValue
+	}
+
+	answer := strings.TrimSpace(p.scanner.Text())
+	if answer == "" {
+		return defaultValue
+	}
+
+	return answer
+}
+
+func splitList(value string) []string {
+	var result []string
+
+	for _, v := range strings.Split(value, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" && !slices.Contains(result, v) {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+// toFieldName converts an input name to a Go field name (ex: dry-run -> DryRun).
+func toFieldName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+
+	for _, part := range parts {
+		runes := []rune(part)
+		b.WriteRune(unicode.ToUpper(runes[0]))
+		b.WriteString(string(runes[1:]))
+	}
+
+	field := b.String()
+	if field == "" || unicode.IsDigit([]rune(field)[0]) {
+		field = "Input" + field
+	}
+
+	return field
+}
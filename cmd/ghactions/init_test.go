@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunInit(t *testing.T) {
+	dir := t.TempDir()
+
+	stdin := strings.NewReader("pull_request, issues\ndry-run\n")
+	stdout := &bytes.Buffer{}
+
+	err := runInit([]string{"-dir", dir, "-name", "my-action"}, stdin, stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		".github/workflows/example.yml",
+		"Dockerfile",
+		"action.yml",
+		"fixtures/issues.json",
+		"fixtures/pull_request.json",
+		"go.mod",
+		"main.go",
+		"main_test.go",
+	}
+
+	for _, path := range expected {
+		_, err = os.Stat(filepath.Join(dir, path))
+		if err != nil {
+			t.Errorf("%s: %v", path, err)
+		}
+	}
+
+	mainGo, err := os.ReadFile(filepath.Join(dir, "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{"action.OnPullRequest(", "action.OnIssues(", `DryRun: getInput("INPUT_DRY-RUN")`} {
+		if !bytes.Contains(mainGo, []byte(s)) {
+			t.Errorf("main.go: missing %q", s)
+		}
+	}
+
+	dockerfile, err := os.ReadFile(filepath.Join(dir, "Dockerfile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the container actions must run as root.
+	if !bytes.Contains(dockerfile, []byte("FROM gcr.io/distroless/static\n")) {
+		t.Errorf("Dockerfile: want a root base image, got:\n%s", dockerfile)
+	}
+
+	// the existing files are not overwritten.
+	err = runInit([]string{"-dir", dir, "-events", "push", "-inputs", ""}, nil, stdout)
+	if err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestRunInit_composite(t *testing.T) {
+	dir := t.TempDir()
+
+	err := runInit([]string{"-dir", dir, "-events", "push", "-inputs", "label", "-runner", "composite"}, nil, &bytes.Buffer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = os.Stat(filepath.Join(dir, "Dockerfile"))
+	if !os.IsNotExist(err) {
+		t.Errorf("Dockerfile: expected no file, got %v", err)
+	}
+
+	actionYml, err := os.ReadFile(filepath.Join(dir, "action.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Contains(actionYml, []byte("INPUT_LABEL: '${{ inputs.label }}'")) {
+		t.Errorf("action.yml: missing input:\n%s", actionYml)
+	}
+}
+
+func TestRunInit_unsupportedEvent(t *testing.T) {
+	err := runInit([]string{"-dir", t.TempDir(), "-events", "unknown", "-inputs", ""}, nil, &bytes.Buffer{})
+	if err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,46 @@
+// Command ghactions Tools to create GitHub Actions with ghactions.
+//
+// Usage:
+//
+//	ghactions init [flags]
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+const usage = `Usage: ghactions <command> [flags]
+
+Commands:
+  init    Generates a new Go action project.
+
+Run 'ghactions <command> -h' for the flags of a command.
+`
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "init":
+		err = runInit(os.Args[2:], os.Stdin, os.Stdout)
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(os.Stdout, usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command: %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,324 @@
+package ghactions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v71/github"
+)
+
+// Repository permission levels.
+const (
+	PermissionNone     = "none"
+	PermissionRead     = "read"
+	PermissionTriage   = "triage"
+	PermissionWrite    = "write"
+	PermissionMaintain = "maintain"
+	PermissionAdmin    = "admin"
+)
+
+// permissionRanks the order of the permission levels.
+var permissionRanks = map[string]int{
+	PermissionNone:     0,
+	PermissionRead:     1,
+	PermissionTriage:   2,
+	PermissionWrite:    3,
+	PermissionMaintain: 4,
+	PermissionAdmin:    5,
+}
+
+// Reactions of the command router.
+const (
+	reactionReceived = "eyes"
+	reactionSuccess  = "rocket"
+	reactionFailure  = "confused"
+)
+
+// Command A slash command found in a comment (e.g. `/deploy production --force --timeout=5m`).
+type Command struct {
+	// Name the name of the command (e.g. /deploy).
+	Name string
+	// Args the positional arguments (e.g. [production]).
+	Args []string
+	// Flags the flags: `--name=value`, `--name` (the value is "true").
+	Flags map[string]string
+	// Event the event of the comment.
+	Event *github.IssueCommentEvent
+}
+
+// Flag Returns the value of a flag, and if the flag is defined.
+func (c *Command) Flag(name string) (string, bool) {
+	value, ok := c.Flags[name]
+	return value, ok
+}
+
+// CommandHandler A handler of a slash command.
+type CommandHandler func(*github.Client, *Command) error
+
+// CommandOption An option of OnCommand.
+type CommandOption func(*command)
+
+// WithPermission Defines the minimum repository permission of the commenter (default: write).
+// The levels are: read, triage, write, maintain, admin.
+func WithPermission(level string) CommandOption {
+	return func(c *command) {
+		c.permission = level
+	}
+}
+
+type command struct {
+	name       string
+	handler    CommandHandler
+	permission string
+}
+
+// OnCommand Slash command handler (event: issue_comment).
+// The commands are lines of the comment body starting with the name of the command,
+// the quoted lines (`>`) and the code blocks are ignored.
+//
+// The command is acknowledged with 👀, and 🚀 or 😕 are added when the handler succeeds or fails.
+// The commands of a commenter without the required permission are skipped (a warning is logged).
+// The handler defined by OnIssueComment is called for the comments without commands.
+func (a *Action) OnCommand(name string, handler CommandHandler, opts ...CommandOption) *Action {
+	cmd := &command{name: name, handler: handler, permission: PermissionWrite}
+	for _, opt := range opts {
+		opt(cmd)
+	}
+
+	a.commands = append(a.commands, cmd)
+
+	return a
+}
+
+// commandsHandler returns the handler of the commands of an event,
+// next (the handler of OnIssueComment) is returned when the event is not a comment with commands.
+func (a *Action) commandsHandler(ctx context.Context, event any, next func(*github.Client) error) func(*github.Client) error {
+	evt, ok := event.(*github.IssueCommentEvent)
+	if !ok || len(a.commands) == 0 {
+		return next
+	}
+
+	commands := a.findCommands(evt)
+	if len(commands) == 0 {
+		if next == nil {
+			// the comments without commands are ignored.
+			return func(*github.Client) error { return nil }
+		}
+
+		return next
+	}
+
+	return func(client *github.Client) error {
+		return a.routeCommands(ctx, client, evt, commands)
+	}
+}
+
+// findCommands finds the registered commands of a new comment.
+func (a *Action) findCommands(event *github.IssueCommentEvent) []*Command {
+	if event.GetAction() != "created" {
+		return nil
+	}
+
+	var commands []*Command
+
+	for _, parsed := range ParseCommands(event.GetComment().GetBody()) {
+		if a.findCommand(parsed.Name) == nil {
+			continue
+		}
+
+		parsed.Event = event
+
+		commands = append(commands, parsed)
+	}
+
+	return commands
+}
+
+// routeCommands dispatches the commands of a comment.
+func (a *Action) routeCommands(ctx context.Context, client *github.Client, event *github.IssueCommentEvent, commands []*Command) error {
+	repo, err := RepoFromEvent(event)
+	if err != nil {
+		return err
+	}
+
+	commentID := event.GetComment().GetID()
+	login := event.GetComment().GetUser().GetLogin()
+
+	permission, err := permissionLevel(ctx, client, repo, login)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, parsed := range commands {
+		cmd := a.findCommand(parsed.Name)
+
+		if !hasPermission(permission, cmd.permission) {
+			a.logger().Warn("skip the command", "command", cmd.name, "comment", commentID,
+				"reason", fmt.Sprintf("%s has the permission %q, %q is required", login, permission, cmd.permission))
+
+			continue
+		}
+
+		a.react(ctx, client, repo, commentID, reactionReceived)
+
+		err = cmd.handler(client, parsed)
+		if err != nil {
+			a.react(ctx, client, repo, commentID, reactionFailure)
+			errs = append(errs, fmt.Errorf("command %s: %w", cmd.name, err))
+
+			continue
+		}
+
+		a.react(ctx, client, repo, commentID, reactionSuccess)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (a *Action) findCommand(name string) *command {
+	for _, cmd := range a.commands {
+		if strings.EqualFold(cmd.name, name) {
+			return cmd
+		}
+	}
+
+	return nil
+}
+
+func (a *Action) react(ctx context.Context, client *github.Client, repo Repo, commentID int64, reaction string) {
+	_, _, err := client.Reactions.CreateIssueCommentReaction(ctx, repo.Owner, repo.Name, commentID, reaction)
+	if err != nil {
+		a.logger().Warn("unable to add the reaction", "reaction", reaction, "comment", commentID, "error", err)
+	}
+}
+
+// permissionLevel returns the repository permission of a user (e.g. write, maintain).
+func permissionLevel(ctx context.Context, client *github.Client, repo Repo, login string) (string, error) {
+	level, _, err := client.Repositories.GetPermissionLevel(ctx, repo.Owner, repo.Name, login)
+	if err != nil {
+		return "", fmt.Errorf("get the permission of %s: %w", login, err)
+	}
+
+	// the role name contains the intermediate levels (triage, maintain).
+	if _, ok := permissionRanks[level.GetRoleName()]; ok {
+		return level.GetRoleName(), nil
+	}
+
+	return level.GetPermission(), nil
+}
+
+// hasPermission checks if a permission level satisfies a minimum level.
+func hasPermission(level, minimum string) bool {
+	return permissionRanks[level] >= permissionRanks[minimum]
+}
+
+// ParseCommands Finds the slash commands of a comment body:
+// the lines starting with `/`, outside the quoted lines (`>`) and the code blocks.
+func ParseCommands(body string) []*Command {
+	var commands []*Command
+
+	var fence string
+
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+
+		if fence != "" {
+			if strings.HasPrefix(line, fence) {
+				fence = ""
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+			fence = line[:3]
+			continue
+		}
+
+		if !strings.HasPrefix(line, "/") {
+			// also ignores the quoted lines.
+			continue
+		}
+
+		words := splitArgs(line)
+		if len(words) == 0 {
+			continue
+		}
+
+		cmd := &Command{Name: words[0], Flags: map[string]string{}}
+
+		for _, word := range words[1:] {
+			if !strings.HasPrefix(word, "-") || word == "-" || word == "--" {
+				cmd.Args = append(cmd.Args, word)
+				continue
+			}
+
+			name, value, ok := strings.Cut(strings.TrimLeft(word, "-"), "=")
+			if !ok {
+				value = "true"
+			}
+
+			cmd.Flags[name] = value
+		}
+
+		commands = append(commands, cmd)
+	}
+
+	return commands
+}
+
+// splitArgs splits a line like a shell: the quotes (`'`, `"`) group the words, `\` escapes a character.
+func splitArgs(line string) []string {
+	var words []string
+
+	var current strings.Builder
+
+	var quote rune
+
+	inWord, escaped := false, false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+
+				inWord = false
+			}
+
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if inWord {
+		words = append(words, current.String())
+	}
+
+	return words
+}
@@ -0,0 +1,286 @@
+package ghactions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v71/github"
+)
+
+func TestParseCommands(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		body     string
+		expected []*Command
+	}{
+		{
+			desc:     "simple",
+			body:     "/deploy",
+			expected: []*Command{{Name: "/deploy", Flags: map[string]string{}}},
+		},
+		{
+			desc: "arguments and flags",
+			body: `please
+/deploy production "eu west" --force --timeout=5m it\'s`,
+			expected: []*Command{{
+				Name:  "/deploy",
+				Args:  []string{"production", "eu west", "it's"},
+				Flags: map[string]string{"force": "true", "timeout": "5m"},
+			}},
+		},
+		{
+			desc: "quoted text",
+			body: "> /deploy production\n\nno",
+		},
+		{
+			desc: "code block",
+			body: "```\n/deploy production\n```\n~~~sh\n/deploy staging\n~~~\n/test 'a b'",
+			expected: []*Command{
+				{Name: "/test", Args: []string{"a b"}, Flags: map[string]string{}},
+			},
+		},
+		{
+			desc: "inline code",
+			body: "`/deploy production`",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			commands := ParseCommands(test.body)
+
+			if !reflect.DeepEqual(commands, test.expected) {
+				t.Errorf("got %s, want %s", printCommands(commands), printCommands(test.expected))
+			}
+		})
+	}
+}
+
+func printCommands(commands []*Command) string {
+	var s []string
+	for _, c := range commands {
+		s = append(s, fmt.Sprintf("%+v", *c))
+	}
+
+	return strings.Join(s, ", ")
+}
+
+func TestAction_OnCommand(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		permission string
+		role       string
+		handlerErr error
+		called     bool
+		reactions  []string
+		expectErr  bool
+	}{
+		{
+			desc:       "success",
+			permission: "write",
+			role:       "write",
+			called:     true,
+			reactions:  []string{"eyes", "rocket"},
+		},
+		{
+			desc:       "maintain role",
+			permission: "write",
+			role:       "maintain",
+			called:     true,
+			reactions:  []string{"eyes", "rocket"},
+		},
+		{
+			desc:       "missing permission",
+			permission: "read",
+			role:       "triage",
+		},
+		{
+			desc:       "handler error",
+			permission: "admin",
+			role:       "admin",
+			handlerErr: errors.New("boom"),
+			called:     true,
+			reactions:  []string{"eyes", "confused"},
+			expectErr:  true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			var mu sync.Mutex
+
+			var reactions []string
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v3/repos/ldez/ghactions/collaborators/octocat/permission", func(rw http.ResponseWriter, _ *http.Request) {
+				_, _ = fmt.Fprintf(rw, `{"permission":%q,"role_name":%q}`, test.permission, test.role)
+			})
+			mux.HandleFunc("POST /api/v3/repos/ldez/ghactions/issues/comments/9/reactions", func(rw http.ResponseWriter, req *http.Request) {
+				var reaction struct {
+					Content string `json:"content"`
+				}
+
+				_ = json.NewDecoder(req.Body).Decode(&reaction)
+
+				mu.Lock()
+				reactions = append(reactions, reaction.Content)
+				mu.Unlock()
+
+				_, _ = rw.Write([]byte(`{}`))
+			})
+
+			action := newTestAction(t, mux)
+
+			var called bool
+
+			action.
+				OnCommand("/deploy", func(_ *github.Client, cmd *Command) error {
+					called = true
+
+					if !slices.Equal(cmd.Args, []string{"production"}) {
+						t.Errorf("got args %v", cmd.Args)
+					}
+
+					return test.handlerErr
+				}).
+				OnCommand("/other", func(*github.Client, *Command) error {
+					t.Error("unexpected command")
+					return nil
+				}, WithPermission(PermissionRead))
+
+			payload := `{"action":"created","comment":{"id":9,"body":"/deploy production","user":{"login":"octocat"}},` +
+				`"issue":{"number":1},"repository":{"full_name":"ldez/ghactions"}}`
+
+			err := action.Handle(context.Background(), "issue_comment", []byte(payload))
+			if test.expectErr && err == nil {
+				t.Error("expected an error")
+			}
+
+			if !test.expectErr && err != nil {
+				t.Fatal(err)
+			}
+
+			if called != test.called {
+				t.Errorf("got called=%v, want %v", called, test.called)
+			}
+
+			if !slices.Equal(reactions, test.reactions) {
+				t.Errorf("got reactions %v, want %v", reactions, test.reactions)
+			}
+		})
+	}
+}
+
+func TestAction_OnCommand_context(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/repos/ldez/ghactions/collaborators/octocat/permission", func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte(`{"permission":"admin","role_name":"admin"}`))
+	})
+	mux.HandleFunc("POST /api/v3/repos/ldez/ghactions/issues/comments/9/reactions", func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte(`{}`))
+	})
+
+	action := newTestAction(t, mux)
+
+	var called bool
+
+	action.OnCommand("/deploy", func(*github.Client, *Command) error {
+		called = true
+		return nil
+	})
+
+	payload := `{"action":"created","comment":{"id":9,"body":"/deploy","user":{"login":"octocat"}},` +
+		`"issue":{"number":1},"repository":{"full_name":"ldez/ghactions"}}`
+
+	// the context of Handle (not the context of NewAction) is used by the API calls.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := action.Handle(ctx, "issue_comment", []byte(payload))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want %v", err, context.Canceled)
+	}
+
+	if called {
+		t.Error("the command has been called with a canceled context")
+	}
+}
+
+func TestAction_OnCommand_issueComment(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/repos/ldez/ghactions/collaborators/octocat/permission", func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte(`{"permission":"admin","role_name":"admin"}`))
+	})
+	mux.HandleFunc("POST /api/v3/repos/ldez/ghactions/issues/comments/9/reactions", func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte(`{}`))
+	})
+
+	testCases := []struct {
+		desc     string
+		action   string
+		body     string
+		expected string
+	}{
+		{
+			desc:     "command",
+			action:   "created",
+			body:     "/deploy",
+			expected: "command",
+		},
+		{
+			desc:     "unknown command",
+			action:   "created",
+			body:     "/unknown",
+			expected: "comment",
+		},
+		{
+			desc:     "without command",
+			action:   "created",
+			body:     "LGTM",
+			expected: "comment",
+		},
+		{
+			desc:     "edited command",
+			action:   "edited",
+			body:     "/deploy",
+			expected: "comment",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			var called []string
+
+			// the handler of OnIssueComment is registered after OnCommand.
+			action := newTestAction(t, mux).
+				OnCommand("/deploy", func(*github.Client, *Command) error {
+					called = append(called, "command")
+					return nil
+				}).
+				OnIssueComment(func(*github.Client, *github.IssueCommentEvent) error {
+					called = append(called, "comment")
+					return nil
+				})
+
+			payload := fmt.Sprintf(`{"action":%q,"comment":{"id":9,"body":%q,"user":{"login":"octocat"}},`+
+				`"issue":{"number":1},"repository":{"full_name":"ldez/ghactions"}}`, test.action, test.body)
+
+			err := action.Handle(context.Background(), "issue_comment", []byte(payload))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(called, []string{test.expected}) {
+				t.Errorf("got %v, want [%s]", called, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package ghactions
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// AnnotationLevel The level of an annotation.
+type AnnotationLevel string
+
+// Annotation levels.
+const (
+	AnnotationNotice  AnnotationLevel = "notice"
+	AnnotationWarning AnnotationLevel = "warning"
+	AnnotationError   AnnotationLevel = "error"
+)
+
+// Annotation The properties of an annotation.
+// https://docs.github.com/en/actions/writing-workflows/choosing-what-your-workflow-does/workflow-commands-for-github-actions#setting-an-error-message
+type Annotation struct {
+	Title     string
+	File      string
+	Line      int
+	EndLine   int
+	Col       int
+	EndColumn int
+}
+
+// properties returns the properties of the workflow command.
+func (a Annotation) properties() []property {
+	var props []property
+
+	if a.Title != "" {
+		props = append(props, property{"title", a.Title})
+	}
+
+	if a.File != "" {
+		props = append(props, property{"file", a.File})
+	}
+
+	for _, p := range []struct {
+		name  string
+		value int
+	}{{"line", a.Line}, {"endLine", a.EndLine}, {"col", a.Col}, {"endColumn", a.EndColumn}} {
+		if p.value > 0 {
+			props = append(props, property{p.name, strconv.Itoa(p.value)})
+		}
+	}
+
+	return props
+}
+
+// commandOutput the output of the workflow commands.
+var (
+	commandOutput   io.Writer = os.Stdout
+	commandOutputMu sync.Mutex
+)
+
+// Annotate Creates an annotation (::notice::, ::warning::, ::error::).
+func Annotate(level AnnotationLevel, message string, annotation Annotation) {
+	issueCommand(string(level), annotation.properties(), message)
+}
+
+// Debug Prints a debug message (::debug::), visible when the debug logging is enabled.
+func Debug(message string) {
+	issueCommand("debug", nil, message)
+}
+
+// StartGroup Starts a collapsible group in the log (::group::).
+// The group opened by a LogHandler is closed (the groups cannot be nested).
+func StartGroup(title string) {
+	closeLogGroup()
+
+	issueCommand("group", nil, title)
+}
+
+// EndGroup Ends the current group (::endgroup::).
+func EndGroup() {
+	logGroup.Lock()
+	defer logGroup.Unlock()
+
+	logGroup.title = ""
+
+	issueCommand("endgroup", nil, "")
+}
+
+type property struct {
+	name  string
+	value string
+}
+
+// issueCommand writes a workflow command: ::name key=value,key=value::message.
+func issueCommand(name string, props []property, message string) {
+	var b strings.Builder
+
+	b.WriteString("::")
+	b.WriteString(name)
+
+	for i, p := range props {
+		if i == 0 {
+			b.WriteString(" ")
+		} else {
+			b.WriteString(",")
+		}
+
+		b.WriteString(p.name)
+		b.WriteString("=")
+		b.WriteString(escapeProperty(p.value))
+	}
+
+	b.WriteString("::")
+	b.WriteString(escapeData(message))
+	b.WriteString("\n")
+
+	writeCommandOutput(b.String())
+}
+
+// writeCommandOutput writes to the output of the workflow commands.
+func writeCommandOutput(s string) {
+	commandOutputMu.Lock()
+	defer commandOutputMu.Unlock()
+
+	_, _ = fmt.Fprint(commandOutput, s)
+}
+
+var (
+	dataEscaper     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	propertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+func escapeData(s string) string {
+	return dataEscaper.Replace(s)
+}
+
+func escapeProperty(s string) string {
+	return propertyEscaper.Replace(s)
+}
+
+// pending the outputs and the step summary written by Flush.
+var pending struct {
+	sync.Mutex
+
+	outputs []property
+	summary strings.Builder
+}
+
+// SetOutput Defines an output of the step (GITHUB_OUTPUT).
+// The outputs are written by Flush (called by Main).
+func SetOutput(name, value string) {
+	pending.Lock()
+	defer pending.Unlock()
+
+	pending.outputs = append(pending.outputs, property{name: name, value: value})
+}
+
+// AddSummary Appends Markdown to the summary of the job (GITHUB_STEP_SUMMARY).
+// The summary is written by Flush (called by Main).
+func AddSummary(markdown string) {
+	pending.Lock()
+	defer pending.Unlock()
+
+	pending.summary.WriteString(markdown)
+
+	if !strings.HasSuffix(markdown, "\n") {
+		pending.summary.WriteString("\n")
+	}
+}
+
+// Flush Writes the pending outputs (GITHUB_OUTPUT) and the pending summary (GITHUB_STEP_SUMMARY).
+// Without these environment variables (e.g. outside a workflow), the pending values are dropped.
+func Flush() error {
+	pending.Lock()
+	defer pending.Unlock()
+
+	var errs []error
+
+	if len(pending.outputs) > 0 {
+		var b strings.Builder
+
+		for _, output := range pending.outputs {
+			delimiter, err := newDelimiter()
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(&b, "%s<<%s\n%s\n%s\n", output.name, delimiter, output.value, delimiter)
+		}
+
+		errs = append(errs, appendToFile(os.Getenv(GithubOutput), b.String()))
+
+		pending.outputs = nil
+	}
+
+	if pending.summary.Len() > 0 {
+		errs = append(errs, appendToFile(os.Getenv(GithubStepSummary), pending.summary.String()))
+
+		pending.summary.Reset()
+	}
+
+	return errors.Join(errs...)
+}
+
+// AddPath Prepends a directory to the PATH of the next steps (GITHUB_PATH), and of the current process.
+func AddPath(dir string) error {
+	err := appendToFile(os.Getenv(GithubPath), dir+"\n")
+	if err != nil {
+		return err
+	}
+
+	return os.Setenv(Path, dir+string(os.PathListSeparator)+os.Getenv(Path))
+}
+
+// newDelimiter creates the delimiter of a multiline value.
+func newDelimiter() (string, error) {
+	b := make([]byte, 16)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return "ghadelimiter_" + hex.EncodeToString(b), nil
+}
+
+// appendToFile appends to a file of the runner (e.g. GITHUB_OUTPUT).
+func appendToFile(path, content string) error {
+	if path == "" {
+		return nil
+	}
+
+	file, err := os.OpenFile(filepath.Clean(path), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.WriteString(content)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,151 @@
+package ghactions
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+// captureCommands captures the workflow commands.
+func captureCommands(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+
+	previous := commandOutput
+	commandOutput = buf
+
+	t.Cleanup(func() { commandOutput = previous })
+
+	return buf
+}
+
+func TestAnnotate(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		level      AnnotationLevel
+		message    string
+		annotation Annotation
+		expected   string
+	}{
+		{
+			desc:     "without properties",
+			level:    AnnotationNotice,
+			message:  "hello",
+			expected: "::notice::hello\n",
+		},
+		{
+			desc:       "with properties",
+			level:      AnnotationError,
+			message:    "line 1\nline 2: 100%",
+			annotation: Annotation{Title: "a: b, c", File: "main.go", Line: 12, EndLine: 13, Col: 2},
+			expected:   "::error title=a%3A b%2C c,file=main.go,line=12,endLine=13,col=2::line 1%0Aline 2: 100%25\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			buf := captureCommands(t)
+
+			Annotate(test.level, test.message, test.annotation)
+
+			if buf.String() != test.expected {
+				t.Errorf("got %q, want %q", buf.String(), test.expected)
+			}
+		})
+	}
+}
+
+func TestGroup(t *testing.T) {
+	buf := captureCommands(t)
+
+	StartGroup("Details")
+	Debug("value")
+	EndGroup()
+
+	expected := "::group::Details\n::debug::value\n::endgroup::\n"
+
+	if buf.String() != expected {
+		t.Errorf("got %q, want %q", buf.String(), expected)
+	}
+}
+
+func TestFlush(t *testing.T) {
+	dir := t.TempDir()
+
+	outputPath := filepath.Join(dir, "output")
+	summaryPath := filepath.Join(dir, "summary")
+
+	t.Setenv(GithubOutput, outputPath)
+	t.Setenv(GithubStepSummary, summaryPath)
+
+	SetOutput("version", "v1.0.0")
+	SetOutput("notes", "a\nb")
+	AddSummary("# Title")
+	AddSummary("text\n")
+
+	err := Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pattern := regexp.MustCompile(`^version<<(ghadelimiter_\w+)\nv1\.0\.0\n(ghadelimiter_\w+)\nnotes<<(ghadelimiter_\w+)\na\nb\n(ghadelimiter_\w+)\n$`)
+
+	m := pattern.FindStringSubmatch(string(output))
+	if m == nil || m[1] != m[2] || m[3] != m[4] {
+		t.Errorf("unexpected outputs: %q", output)
+	}
+
+	summary, err := os.ReadFile(summaryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(summary) != "# Title\ntext\n" {
+		t.Errorf("got summary %q", summary)
+	}
+
+	// the pending values are written once.
+	err = Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary, _ = os.ReadFile(summaryPath)
+	if string(summary) != "# Title\ntext\n" {
+		t.Errorf("got summary %q", summary)
+	}
+}
+
+func TestAddPath(t *testing.T) {
+	pathFile := filepath.Join(t.TempDir(), "path")
+
+	t.Setenv(GithubPath, pathFile)
+	t.Setenv(Path, "/usr/bin")
+
+	err := AddPath("/opt/tool/bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(pathFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "/opt/tool/bin\n" {
+		t.Errorf("got %q", content)
+	}
+
+	expected := "/opt/tool/bin" + string(os.PathListSeparator) + "/usr/bin"
+	if os.Getenv(Path) != expected {
+		t.Errorf("got PATH %q, want %q", os.Getenv(Path), expected)
+	}
+}
@@ -0,0 +1,227 @@
+package ghactions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v71/github"
+)
+
+// defaultCommentAuthor the login of the GITHUB_TOKEN of a workflow.
+const defaultCommentAuthor = "github-actions[bot]"
+
+// StickyCommentOption An option of StickyComment and DeleteStickyComment.
+type StickyCommentOption func(*stickyCommentConfig)
+
+type stickyCommentConfig struct {
+	append bool
+	author string
+}
+
+// WithAppend Appends the body to the existing comment instead of replacing it.
+func WithAppend() StickyCommentOption {
+	return func(cfg *stickyCommentConfig) {
+		cfg.append = true
+	}
+}
+
+// WithCommentAuthor Defines the login of the author of the comment.
+// By default, the authenticated user is used (github-actions[bot] when it cannot be resolved, e.g. with GITHUB_TOKEN).
+// It is required with the clients of a ClientFactory authenticated as an installation (e.g. AppInstallations.Login),
+// and when GITHUB_TOKEN is not the token of the workflow (e.g. the token of a GitHub App).
+func WithCommentAuthor(login string) StickyCommentOption {
+	return func(cfg *stickyCommentConfig) {
+		cfg.author = login
+	}
+}
+
+// StickyComment Creates or updates a comment identified by a key on an issue or a pull request.
+// The comment is found by a hidden HTML marker and by its author (the authenticated user):
+// the next runs update the same comment instead of adding a new one.
+//
+// The event is the event given to the handler (Run or Handle), it can be nil outside a handler (GITHUB_REPOSITORY is used).
+// When issueNumber is 0, the number is taken from the event (issues, issue_comment, pull_request, pull_request_target).
+func (a *Action) StickyComment(ctx context.Context, event any, issueNumber int, key, body string, opts ...StickyCommentOption) (*github.IssueComment, error) {
+	sc, err := a.newStickyComment(ctx, event, issueNumber, key, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := sc.find(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing == nil {
+		comment, _, err := sc.client.Issues.CreateComment(ctx, sc.repo.Owner, sc.repo.Name, sc.number,
+			&github.IssueComment{Body: github.Ptr(sc.marker() + "\n" + body)})
+		if err != nil {
+			return nil, fmt.Errorf("create the comment %q on #%d: %w", key, sc.number, err)
+		}
+
+		return comment, nil
+	}
+
+	content := sc.marker() + "\n" + body
+	if sc.cfg.append {
+		content = strings.TrimRight(existing.GetBody(), "\n") + "\n\n" + body
+	}
+
+	comment, _, err := sc.client.Issues.EditComment(ctx, sc.repo.Owner, sc.repo.Name, existing.GetID(),
+		&github.IssueComment{Body: github.Ptr(content)})
+	if err != nil {
+		return nil, fmt.Errorf("update the comment %q on #%d: %w", key, sc.number, err)
+	}
+
+	return comment, nil
+}
+
+// DeleteStickyComment Deletes the comment identified by a key (see StickyComment).
+// Does nothing when the comment does not exist.
+func (a *Action) DeleteStickyComment(ctx context.Context, event any, issueNumber int, key string, opts ...StickyCommentOption) error {
+	sc, err := a.newStickyComment(ctx, event, issueNumber, key, opts)
+	if err != nil {
+		return err
+	}
+
+	existing, err := sc.find(ctx)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		return nil
+	}
+
+	_, err = sc.client.Issues.DeleteComment(ctx, sc.repo.Owner, sc.repo.Name, existing.GetID())
+	if err != nil {
+		return fmt.Errorf("delete the comment %q on #%d: %w", key, sc.number, err)
+	}
+
+	return nil
+}
+
+type stickyComment struct {
+	client *github.Client
+	repo   Repo
+	number int
+	key    string
+	cfg    *stickyCommentConfig
+	// factory the client is created by a ClientFactory.
+	factory bool
+}
+
+func (a *Action) newStickyComment(ctx context.Context, event any, issueNumber int, key string, opts []StickyCommentOption) (*stickyComment, error) {
+	if key == "" {
+		return nil, errors.New("sticky comment: the key is required")
+	}
+
+	if strings.Contains(key, "-->") {
+		// would end the HTML marker.
+		return nil, fmt.Errorf("sticky comment: invalid key %q: must not contain \"-->\"", key)
+	}
+
+	cfg := &stickyCommentConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if issueNumber == 0 {
+		issueNumber = eventIssueNumber(event)
+		if issueNumber == 0 {
+			return nil, errors.New("sticky comment: unable to find the issue number from the event")
+		}
+	}
+
+	repo, err := repoFor(event)
+	if err != nil {
+		return nil, fmt.Errorf("sticky comment: %w", err)
+	}
+
+	client, err := a.clientFor(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stickyComment{client: client, repo: repo, number: issueNumber, key: key, cfg: cfg, factory: a.ClientFactory != nil}, nil
+}
+
+// marker the hidden HTML marker of the comment.
+func (s *stickyComment) marker() string {
+	return fmt.Sprintf("<!-- ghactions:sticky-comment:%s -->", s.key)
+}
+
+// find finds the comment with the marker written by the author.
+func (s *stickyComment) find(ctx context.Context) (*github.IssueComment, error) {
+	author, err := s.author(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+
+	for {
+		comments, resp, err := s.client.Issues.ListComments(ctx, s.repo.Owner, s.repo.Name, s.number, opts)
+		if err != nil {
+			return nil, fmt.Errorf("list the comments of #%d: %w", s.number, err)
+		}
+
+		for _, comment := range comments {
+			if strings.EqualFold(comment.GetUser().GetLogin(), author) && strings.Contains(comment.GetBody(), s.marker()) {
+				return comment, nil
+			}
+		}
+
+		if resp.NextPage == 0 {
+			return nil, nil //nolint:nilnil // no comment found.
+		}
+
+		opts.Page = resp.NextPage
+	}
+}
+
+func (s *stickyComment) author(ctx context.Context) (string, error) {
+	if s.cfg.author != "" {
+		return s.cfg.author, nil
+	}
+
+	user, resp, err := s.client.Users.Get(ctx, "")
+	if err != nil {
+		if resp != nil && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden) {
+			if s.factory {
+				// the login of an App installation is "<app-slug>[bot]".
+				return "", errors.New("sticky comment: unable to get the author of the comment from an installation token: use WithCommentAuthor")
+			}
+
+			// installation tokens (GITHUB_TOKEN) cannot read the authenticated user.
+			return defaultCommentAuthor, nil
+		}
+
+		return "", fmt.Errorf("get the authenticated user: %w", err)
+	}
+
+	return user.GetLogin(), nil
+}
+
+// eventIssueNumber returns the number of the issue or the pull request of an event.
+func eventIssueNumber(event any) int {
+	switch evt := event.(type) {
+	case *github.IssuesEvent:
+		return evt.GetIssue().GetNumber()
+	case *github.IssueCommentEvent:
+		return evt.GetIssue().GetNumber()
+	case *github.PullRequestEvent:
+		return evt.GetPullRequest().GetNumber()
+	case *github.PullRequestTargetEvent:
+		return evt.GetPullRequest().GetNumber()
+	case *github.PullRequestReviewEvent:
+		return evt.GetPullRequest().GetNumber()
+	case *github.PullRequestReviewCommentEvent:
+		return evt.GetPullRequest().GetNumber()
+	default:
+		return 0
+	}
+}
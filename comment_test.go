@@ -0,0 +1,186 @@
+package ghactions
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v71/github"
+)
+
+func TestAction_StickyComment(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		comments string
+		opts     []StickyCommentOption
+		expected string
+		method   string
+	}{
+		{
+			desc:     "create",
+			comments: `[{"id":1,"body":"hello","user":{"login":"github-actions[bot]"}}]`,
+			expected: "<!-- ghactions:sticky-comment:report -->\nnew",
+			method:   http.MethodPost,
+		},
+		{
+			desc:     "marker from another author",
+			comments: `[{"id":1,"body":"<!-- ghactions:sticky-comment:report -->\nold","user":{"login":"octocat"}}]`,
+			expected: "<!-- ghactions:sticky-comment:report -->\nnew",
+			method:   http.MethodPost,
+		},
+		{
+			desc:     "replace",
+			comments: `[{"id":1,"body":"<!-- ghactions:sticky-comment:report -->\nold","user":{"login":"github-actions[bot]"}}]`,
+			expected: "<!-- ghactions:sticky-comment:report -->\nnew",
+			method:   http.MethodPatch,
+		},
+		{
+			desc:     "append",
+			comments: `[{"id":1,"body":"<!-- ghactions:sticky-comment:report -->\nold\n","user":{"login":"github-actions[bot]"}}]`,
+			opts:     []StickyCommentOption{WithAppend()},
+			expected: "<!-- ghactions:sticky-comment:report -->\nold\n\nnew",
+			method:   http.MethodPatch,
+		},
+		{
+			desc:     "explicit author",
+			comments: `[{"id":1,"body":"<!-- ghactions:sticky-comment:report -->\nold","user":{"login":"my-app[bot]"}}]`,
+			opts:     []StickyCommentOption{WithCommentAuthor("my-app[bot]")},
+			expected: "<!-- ghactions:sticky-comment:report -->\nnew",
+			method:   http.MethodPatch,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			var method, body string
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v3/user", func(rw http.ResponseWriter, _ *http.Request) {
+				http.Error(rw, `{"message":"Resource not accessible by integration"}`, http.StatusForbidden)
+			})
+			mux.HandleFunc("GET /api/v3/repos/ldez/ghactions/issues/7/comments", func(rw http.ResponseWriter, _ *http.Request) {
+				_, _ = rw.Write([]byte(test.comments))
+			})
+			handle := func(rw http.ResponseWriter, req *http.Request) {
+				method = req.Method
+
+				var comment struct {
+					Body string `json:"body"`
+				}
+
+				_ = json.NewDecoder(req.Body).Decode(&comment)
+				body = comment.Body
+
+				_, _ = rw.Write([]byte(`{"id":1}`))
+			}
+			mux.HandleFunc("POST /api/v3/repos/ldez/ghactions/issues/7/comments", handle)
+			mux.HandleFunc("PATCH /api/v3/repos/ldez/ghactions/issues/comments/1", handle)
+
+			action := newTestAction(t, mux)
+
+			event := &github.PullRequestEvent{}
+			unmarshal(t, `{"number":7,"pull_request":{"number":7},"repository":{"full_name":"ldez/ghactions"}}`, event)
+
+			_, err := action.StickyComment(context.Background(), event, 0, "report", "new", test.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if method != test.method {
+				t.Errorf("got method %s, want %s", method, test.method)
+			}
+
+			if body != test.expected {
+				t.Errorf("got body %q, want %q", body, test.expected)
+			}
+		})
+	}
+}
+
+func TestAction_DeleteStickyComment(t *testing.T) {
+	var deleted bool
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/user", func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte(`{"login":"octocat"}`))
+	})
+	mux.HandleFunc("GET /api/v3/repos/ldez/ghactions/issues/3/comments", func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte(`[{"id":1,"body":"<!-- ghactions:sticky-comment:other -->","user":{"login":"octocat"}},` +
+			`{"id":2,"body":"<!-- ghactions:sticky-comment:report -->","user":{"login":"octocat"}}]`))
+	})
+	mux.HandleFunc("DELETE /api/v3/repos/ldez/ghactions/issues/comments/2", func(rw http.ResponseWriter, _ *http.Request) {
+		deleted = true
+		rw.WriteHeader(http.StatusNoContent)
+	})
+
+	action := newTestAction(t, mux)
+
+	event := &github.IssuesEvent{}
+	unmarshal(t, `{"issue":{"number":3},"repository":{"full_name":"ldez/ghactions"}}`, event)
+
+	err := action.DeleteStickyComment(context.Background(), event, 3, "report")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !deleted {
+		t.Error("the comment must be deleted")
+	}
+}
+
+func TestAction_StickyComment_errors(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/user", func(rw http.ResponseWriter, _ *http.Request) {
+		http.Error(rw, `{"message":"Resource not accessible by integration"}`, http.StatusForbidden)
+	})
+	mux.HandleFunc("GET /api/v3/repos/ldez/ghactions/issues/7/comments", func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte(`[]`))
+	})
+
+	event := &github.PullRequestEvent{}
+	unmarshal(t, `{"number":7,"pull_request":{"number":7},"repository":{"full_name":"ldez/ghactions"}}`, event)
+
+	testCases := []struct {
+		desc    string
+		factory bool
+		key     string
+		event   any
+	}{
+		{
+			desc: "end of the marker in the key",
+			key:  "report -->",
+		},
+		{
+			desc:  "event without repository",
+			key:   "report",
+			event: &github.PullRequestEvent{Number: github.Ptr(7)},
+		},
+		{
+			desc:    "installation without author",
+			factory: true,
+			key:     "report",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			action := newTestAction(t, mux)
+
+			if test.factory {
+				client := action.client
+				action.ClientFactory = func(context.Context, any) (*github.Client, error) { return client, nil }
+			}
+
+			evt := test.event
+			if evt == nil {
+				evt = event
+			}
+
+			_, err := action.StickyComment(context.Background(), evt, 0, test.key, "new")
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
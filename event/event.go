@@ -0,0 +1,28 @@
+// Package event GitHub event names.
+//
+// The constants, the activity types and the payload types are generated from the events supported by go-github,
+// and from the events handled by ghactions with its own types (ex: ghactions.ScheduleEvent).
+package event
+
+import (
+	"reflect"
+	"slices"
+)
+
+// Actions Returns the valid actions (activity types) of an event.
+// Returns nil when the event has no activity types or is unknown.
+func Actions(name string) []string {
+	return slices.Clone(activityTypes[name])
+}
+
+// IsValidAction Checks if an action is a valid activity type of an event.
+func IsValidAction(name, action string) bool {
+	return slices.Contains(activityTypes[name], action)
+}
+
+// PayloadType Returns the type of the payload of an event (ex: github.PullRequestEvent for "pull_request").
+// The events not parsed by github.ParseWebHook have the types of ghactions (ex: ghactions.ScheduleEvent for "schedule").
+// Returns nil when the event has no handler (ex: "workflow_call") or is unknown.
+func PayloadType(name string) reflect.Type {
+	return payloadTypes[name]
+}
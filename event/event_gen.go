@@ -0,0 +1,237 @@
+// Code generated by internal/gen; DO NOT EDIT.
+
+package event
+
+import (
+	"reflect"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/ldez/ghactions"
+)
+
+// Webhook events, parsed by github.ParseWebHook.
+const (
+	BranchProtectionConfiguration = "branch_protection_configuration"
+	BranchProtectionRule          = "branch_protection_rule"
+	CheckRun                      = "check_run"
+	CheckSuite                    = "check_suite"
+	CodeScanningAlert             = "code_scanning_alert"
+	CommitComment                 = "commit_comment"
+	ContentReference              = "content_reference"
+	Create                        = "create"
+	CustomProperty                = "custom_property"
+	CustomPropertyValues          = "custom_property_values"
+	Delete                        = "delete"
+	DependabotAlert               = "dependabot_alert"
+	DeployKey                     = "deploy_key"
+	Deployment                    = "deployment"
+	DeploymentProtectionRule      = "deployment_protection_rule"
+	DeploymentReview              = "deployment_review"
+	DeploymentStatus              = "deployment_status"
+	Discussion                    = "discussion"
+	DiscussionComment             = "discussion_comment"
+	Fork                          = "fork"
+	GitHubAppAuthorization        = "github_app_authorization"
+	Gollum                        = "gollum"
+	Installation                  = "installation"
+	InstallationRepositories      = "installation_repositories"
+	InstallationTarget            = "installation_target"
+	IssueComment                  = "issue_comment"
+	Issues                        = "issues"
+	Label                         = "label"
+	MarketplacePurchase           = "marketplace_purchase"
+	Member                        = "member"
+	Membership                    = "membership"
+	MergeGroup                    = "merge_group"
+	Meta                          = "meta"
+	Milestone                     = "milestone"
+	OrgBlock                      = "org_block"
+	Organization                  = "organization"
+	Package                       = "package"
+	PageBuild                     = "page_build"
+	PersonalAccessTokenRequest    = "personal_access_token_request"
+	Ping                          = "ping"
+	ProjectV2                     = "projects_v2"
+	ProjectV2Item                 = "projects_v2_item"
+	Public                        = "public"
+	PullRequest                   = "pull_request"
+	PullRequestReview             = "pull_request_review"
+	PullRequestReviewComment      = "pull_request_review_comment"
+	PullRequestReviewThread       = "pull_request_review_thread"
+	PullRequestTarget             = "pull_request_target"
+	Push                          = "push"
+	Release                       = "release"
+	Repository                    = "repository"
+	RepositoryDispatch            = "repository_dispatch"
+	RepositoryImport              = "repository_import"
+	RepositoryRuleset             = "repository_ruleset"
+	RepositoryVulnerabilityAlert  = "repository_vulnerability_alert"
+	SecretScanningAlert           = "secret_scanning_alert"
+	SecretScanningAlertLocation   = "secret_scanning_alert_location"
+	SecurityAdvisory              = "security_advisory"
+	SecurityAndAnalysis           = "security_and_analysis"
+	Sponsorship                   = "sponsorship"
+	Star                          = "star"
+	Status                        = "status"
+	Team                          = "team"
+	TeamAdd                       = "team_add"
+	User                          = "user"
+	Watch                         = "watch"
+	WorkflowDispatch              = "workflow_dispatch"
+	WorkflowJob                   = "workflow_job"
+	WorkflowRun                   = "workflow_run"
+)
+
+// Webhook events and workflow triggers, not parsed by github.ParseWebHook.
+const (
+	Project            = "project"
+	ProjectCard        = "project_card"
+	ProjectColumn      = "project_column"
+	RegistryPackage    = "registry_package"
+	RepositoryAdvisory = "repository_advisory"
+	Schedule           = "schedule"
+	WorkflowCall       = "workflow_call"
+)
+
+// activityTypes the valid actions of the events.
+var activityTypes = map[string][]string{
+	BranchProtectionConfiguration: {"disabled", "enabled"},
+	BranchProtectionRule:          {"created", "deleted", "edited"},
+	CheckRun:                      {"completed", "created", "requested_action", "rerequested"},
+	CheckSuite:                    {"completed", "requested", "rerequested"},
+	CodeScanningAlert:             {"appeared_in_branch", "closed_by_user", "created", "fixed", "reopened", "reopened_by_user"},
+	CommitComment:                 {"created"},
+	ContentReference:              {"created"},
+	CustomProperty:                {"created", "deleted", "promote_to_enterprise", "updated"},
+	CustomPropertyValues:          {"updated"},
+	DependabotAlert:               {"auto_dismissed", "auto_reopened", "created", "dismissed", "fixed", "reintroduced", "reopened"},
+	DeployKey:                     {"created", "deleted"},
+	Deployment:                    {"created"},
+	DeploymentProtectionRule:      {"requested"},
+	DeploymentReview:              {"approved", "rejected", "requested"},
+	DeploymentStatus:              {"created"},
+	Discussion:                    {"answered", "category_changed", "closed", "created", "deleted", "edited", "labeled", "locked", "pinned", "reopened", "transferred", "unanswered", "unlabeled", "unlocked", "unpinned"},
+	DiscussionComment:             {"created", "deleted", "edited"},
+	GitHubAppAuthorization:        {"revoked"},
+	Installation:                  {"created", "deleted", "new_permissions_accepted", "suspend", "unsuspend"},
+	InstallationRepositories:      {"added", "removed"},
+	InstallationTarget:            {"renamed"},
+	IssueComment:                  {"created", "deleted", "edited"},
+	Issues:                        {"assigned", "closed", "deleted", "demilestoned", "edited", "labeled", "locked", "milestoned", "opened", "pinned", "reopened", "transferred", "typed", "unassigned", "unlabeled", "unlocked", "unpinned", "untyped"},
+	Label:                         {"created", "deleted", "edited"},
+	MarketplacePurchase:           {"cancelled", "changed", "pending_change", "pending_change_cancelled", "purchased"},
+	Member:                        {"added", "edited", "removed"},
+	Membership:                    {"added", "removed"},
+	MergeGroup:                    {"checks_requested", "destroyed"},
+	Meta:                          {"deleted"},
+	Milestone:                     {"closed", "created", "deleted", "edited", "opened"},
+	OrgBlock:                      {"blocked", "unblocked"},
+	Organization:                  {"deleted", "member_added", "member_invited", "member_removed", "renamed"},
+	Package:                       {"published", "updated"},
+	PersonalAccessTokenRequest:    {"approved", "cancelled", "created", "denied"},
+	ProjectV2:                     {"closed", "created", "deleted", "edited", "reopened"},
+	ProjectV2Item:                 {"archived", "converted", "created", "deleted", "edited", "reordered", "restored"},
+	PullRequest:                   {"assigned", "auto_merge_disabled", "auto_merge_enabled", "closed", "converted_to_draft", "demilestoned", "dequeued", "edited", "enqueued", "labeled", "locked", "milestoned", "opened", "ready_for_review", "reopened", "review_request_removed", "review_requested", "synchronize", "unassigned", "unlabeled", "unlocked"},
+	PullRequestReview:             {"dismissed", "edited", "submitted"},
+	PullRequestReviewComment:      {"created", "deleted", "edited"},
+	PullRequestReviewThread:       {"resolved", "unresolved"},
+	PullRequestTarget:             {"assigned", "auto_merge_disabled", "auto_merge_enabled", "closed", "converted_to_draft", "demilestoned", "dequeued", "edited", "enqueued", "labeled", "locked", "milestoned", "opened", "ready_for_review", "reopened", "review_request_removed", "review_requested", "synchronize", "unassigned", "unlabeled", "unlocked"},
+	Release:                       {"created", "deleted", "edited", "prereleased", "published", "released", "unpublished"},
+	Repository:                    {"archived", "created", "deleted", "edited", "privatized", "publicized", "renamed", "transferred", "unarchived"},
+	RepositoryRuleset:             {"created", "deleted", "edited"},
+	RepositoryVulnerabilityAlert:  {"create", "dismiss", "reopen", "resolve"},
+	SecretScanningAlert:           {"created", "publicly_leaked", "reopened", "resolved", "validated"},
+	SecretScanningAlertLocation:   {"created"},
+	SecurityAdvisory:              {"published", "updated", "withdrawn"},
+	Sponsorship:                   {"cancelled", "created", "edited", "pending_cancellation", "pending_tier_change", "tier_changed"},
+	Star:                          {"created", "deleted"},
+	Team:                          {"added_to_repository", "created", "deleted", "edited", "removed_from_repository"},
+	User:                          {"created", "deleted"},
+	Watch:                         {"started"},
+	WorkflowJob:                   {"completed", "in_progress", "queued", "waiting"},
+	WorkflowRun:                   {"completed", "in_progress", "requested"},
+	Project:                       {"closed", "created", "deleted", "edited", "reopened"},
+	ProjectCard:                   {"converted", "created", "deleted", "edited", "moved"},
+	ProjectColumn:                 {"created", "deleted", "edited", "moved"},
+	RegistryPackage:               {"published", "updated"},
+	RepositoryAdvisory:            {"published", "reported"},
+}
+
+// payloadTypes the Go types of the payloads.
+var payloadTypes = map[string]reflect.Type{
+	BranchProtectionConfiguration: reflect.TypeFor[github.BranchProtectionConfigurationEvent](),
+	BranchProtectionRule:          reflect.TypeFor[github.BranchProtectionRuleEvent](),
+	CheckRun:                      reflect.TypeFor[github.CheckRunEvent](),
+	CheckSuite:                    reflect.TypeFor[github.CheckSuiteEvent](),
+	CodeScanningAlert:             reflect.TypeFor[github.CodeScanningAlertEvent](),
+	CommitComment:                 reflect.TypeFor[github.CommitCommentEvent](),
+	ContentReference:              reflect.TypeFor[github.ContentReferenceEvent](),
+	Create:                        reflect.TypeFor[github.CreateEvent](),
+	CustomProperty:                reflect.TypeFor[github.CustomPropertyEvent](),
+	CustomPropertyValues:          reflect.TypeFor[github.CustomPropertyValuesEvent](),
+	Delete:                        reflect.TypeFor[github.DeleteEvent](),
+	DependabotAlert:               reflect.TypeFor[github.DependabotAlertEvent](),
+	DeployKey:                     reflect.TypeFor[github.DeployKeyEvent](),
+	Deployment:                    reflect.TypeFor[github.DeploymentEvent](),
+	DeploymentProtectionRule:      reflect.TypeFor[github.DeploymentProtectionRuleEvent](),
+	DeploymentReview:              reflect.TypeFor[github.DeploymentReviewEvent](),
+	DeploymentStatus:              reflect.TypeFor[github.DeploymentStatusEvent](),
+	Discussion:                    reflect.TypeFor[github.DiscussionEvent](),
+	DiscussionComment:             reflect.TypeFor[github.DiscussionCommentEvent](),
+	Fork:                          reflect.TypeFor[github.ForkEvent](),
+	GitHubAppAuthorization:        reflect.TypeFor[github.GitHubAppAuthorizationEvent](),
+	Gollum:                        reflect.TypeFor[github.GollumEvent](),
+	Installation:                  reflect.TypeFor[github.InstallationEvent](),
+	InstallationRepositories:      reflect.TypeFor[github.InstallationRepositoriesEvent](),
+	InstallationTarget:            reflect.TypeFor[github.InstallationTargetEvent](),
+	IssueComment:                  reflect.TypeFor[github.IssueCommentEvent](),
+	Issues:                        reflect.TypeFor[github.IssuesEvent](),
+	Label:                         reflect.TypeFor[github.LabelEvent](),
+	MarketplacePurchase:           reflect.TypeFor[github.MarketplacePurchaseEvent](),
+	Member:                        reflect.TypeFor[github.MemberEvent](),
+	Membership:                    reflect.TypeFor[github.MembershipEvent](),
+	MergeGroup:                    reflect.TypeFor[github.MergeGroupEvent](),
+	Meta:                          reflect.TypeFor[github.MetaEvent](),
+	Milestone:                     reflect.TypeFor[github.MilestoneEvent](),
+	OrgBlock:                      reflect.TypeFor[github.OrgBlockEvent](),
+	Organization:                  reflect.TypeFor[github.OrganizationEvent](),
+	Package:                       reflect.TypeFor[github.PackageEvent](),
+	PageBuild:                     reflect.TypeFor[github.PageBuildEvent](),
+	PersonalAccessTokenRequest:    reflect.TypeFor[github.PersonalAccessTokenRequestEvent](),
+	Ping:                          reflect.TypeFor[github.PingEvent](),
+	ProjectV2:                     reflect.TypeFor[github.ProjectV2Event](),
+	ProjectV2Item:                 reflect.TypeFor[github.ProjectV2ItemEvent](),
+	Public:                        reflect.TypeFor[github.PublicEvent](),
+	PullRequest:                   reflect.TypeFor[github.PullRequestEvent](),
+	PullRequestReview:             reflect.TypeFor[github.PullRequestReviewEvent](),
+	PullRequestReviewComment:      reflect.TypeFor[github.PullRequestReviewCommentEvent](),
+	PullRequestReviewThread:       reflect.TypeFor[github.PullRequestReviewThreadEvent](),
+	PullRequestTarget:             reflect.TypeFor[github.PullRequestTargetEvent](),
+	Push:                          reflect.TypeFor[github.PushEvent](),
+	Release:                       reflect.TypeFor[github.ReleaseEvent](),
+	Repository:                    reflect.TypeFor[github.RepositoryEvent](),
+	RepositoryDispatch:            reflect.TypeFor[github.RepositoryDispatchEvent](),
+	RepositoryImport:              reflect.TypeFor[github.RepositoryImportEvent](),
+	RepositoryRuleset:             reflect.TypeFor[github.RepositoryRulesetEvent](),
+	RepositoryVulnerabilityAlert:  reflect.TypeFor[github.RepositoryVulnerabilityAlertEvent](),
+	SecretScanningAlert:           reflect.TypeFor[github.SecretScanningAlertEvent](),
+	SecretScanningAlertLocation:   reflect.TypeFor[github.SecretScanningAlertLocationEvent](),
+	SecurityAdvisory:              reflect.TypeFor[github.SecurityAdvisoryEvent](),
+	SecurityAndAnalysis:           reflect.TypeFor[github.SecurityAndAnalysisEvent](),
+	Sponsorship:                   reflect.TypeFor[github.SponsorshipEvent](),
+	Star:                          reflect.TypeFor[github.StarEvent](),
+	Status:                        reflect.TypeFor[github.StatusEvent](),
+	Team:                          reflect.TypeFor[github.TeamEvent](),
+	TeamAdd:                       reflect.TypeFor[github.TeamAddEvent](),
+	User:                          reflect.TypeFor[github.UserEvent](),
+	Watch:                         reflect.TypeFor[github.WatchEvent](),
+	WorkflowDispatch:              reflect.TypeFor[github.WorkflowDispatchEvent](),
+	WorkflowJob:                   reflect.TypeFor[github.WorkflowJobEvent](),
+	WorkflowRun:                   reflect.TypeFor[github.WorkflowRunEvent](),
+	Project:                       reflect.TypeFor[ghactions.ClassicProjectEvent](),
+	ProjectCard:                   reflect.TypeFor[ghactions.ClassicProjectCardEvent](),
+	ProjectColumn:                 reflect.TypeFor[ghactions.ClassicProjectColumnEvent](),
+	RegistryPackage:               reflect.TypeFor[ghactions.RegistryPackageEvent](),
+	RepositoryAdvisory:            reflect.TypeFor[ghactions.RepositoryAdvisoryEvent](),
+	Schedule:                      reflect.TypeFor[ghactions.ScheduleEvent](),
+}
@@ -0,0 +1,55 @@
+package event
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/ldez/ghactions/internal/events"
+)
+
+func TestPayloadType(t *testing.T) {
+	for _, name := range github.MessageTypes() {
+		typ := PayloadType(name)
+		if typ == nil {
+			t.Errorf("%s: missing payload type", name)
+			continue
+		}
+
+		expected := reflect.TypeOf(github.EventForType(name)).Elem()
+		if typ != expected {
+			t.Errorf("%s: got %s, want %s", name, typ, expected)
+		}
+	}
+
+	for name, typeName := range events.Local {
+		typ := PayloadType(name)
+		if typ == nil || typ.Name() != typeName || typ.PkgPath() != "github.com/ldez/ghactions" {
+			t.Errorf("%s: got %v, want ghactions.%s", name, typ, typeName)
+		}
+	}
+
+	if PayloadType(WorkflowCall) != nil {
+		t.Errorf("%s: expected no payload type", WorkflowCall)
+	}
+}
+
+func TestIsValidAction(t *testing.T) {
+	testCases := []struct {
+		event    string
+		action   string
+		expected bool
+	}{
+		{event: PullRequest, action: "opened", expected: true},
+		{event: PullRequestTarget, action: "synchronize", expected: true},
+		{event: Issues, action: "synchronize", expected: false},
+		{event: Push, action: "created", expected: false},
+		{event: "unknown", action: "created", expected: false},
+	}
+
+	for _, test := range testCases {
+		if IsValidAction(test.event, test.action) != test.expected {
+			t.Errorf("%s/%s: expected %v", test.event, test.action, test.expected)
+		}
+	}
+}
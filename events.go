@@ -0,0 +1,589 @@
+package ghactions
+
+import (
+	"encoding/json"
+
+	"github.com/google/go-github/v71/github"
+)
+
+// The webhook events not parsed by github.ParseWebHook (go-github v71): classic projects, registry packages,
+// and repository advisories.
+
+// ClassicProjectEvent Triggered when a classic project is created, updated, or deleted (event: project).
+//
+// GitHub API docs: https://docs.github.com/en/webhooks/webhook-events-and-payloads#project
+type ClassicProjectEvent struct {
+	Action  *string               `json:"action,omitempty"`
+	Changes *github.ProjectChange `json:"changes,omitempty"`
+	Project *ClassicProject       `json:"project,omitempty"`
+
+	Repository   *github.Repository   `json:"repository,omitempty"`
+	Organization *github.Organization `json:"organization,omitempty"`
+	Sender       *github.User         `json:"sender,omitempty"`
+	Installation *github.Installation `json:"installation,omitempty"`
+}
+
+// GetAction returns the Action field if it's non-nil, zero value otherwise.
+func (e *ClassicProjectEvent) GetAction() string {
+	if e == nil || e.Action == nil {
+		return ""
+	}
+	return *e.Action
+}
+
+// GetChanges returns the Changes field.
+func (e *ClassicProjectEvent) GetChanges() *github.ProjectChange {
+	if e == nil {
+		return nil
+	}
+	return e.Changes
+}
+
+// GetProject returns the Project field.
+func (e *ClassicProjectEvent) GetProject() *ClassicProject {
+	if e == nil {
+		return nil
+	}
+	return e.Project
+}
+
+// GetRepository returns the Repository field.
+func (e *ClassicProjectEvent) GetRepository() *github.Repository {
+	if e == nil {
+		return nil
+	}
+	return e.Repository
+}
+
+// GetOrganization returns the Organization field.
+func (e *ClassicProjectEvent) GetOrganization() *github.Organization {
+	if e == nil {
+		return nil
+	}
+	return e.Organization
+}
+
+// GetSender returns the Sender field.
+func (e *ClassicProjectEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+// GetInstallation returns the Installation field.
+func (e *ClassicProjectEvent) GetInstallation() *github.Installation {
+	if e == nil {
+		return nil
+	}
+	return e.Installation
+}
+
+// ClassicProjectCardEvent Triggered when a card of a classic project is created, updated, moved, or deleted (event: project_card).
+//
+// GitHub API docs: https://docs.github.com/en/webhooks/webhook-events-and-payloads#project_card
+type ClassicProjectCardEvent struct {
+	Action      *string                   `json:"action,omitempty"`
+	Changes     *github.ProjectCardChange `json:"changes,omitempty"`
+	AfterID     *int64                    `json:"after_id,omitempty"`
+	ProjectCard *ClassicProjectCard       `json:"project_card,omitempty"`
+
+	Repository   *github.Repository   `json:"repository,omitempty"`
+	Organization *github.Organization `json:"organization,omitempty"`
+	Sender       *github.User         `json:"sender,omitempty"`
+	Installation *github.Installation `json:"installation,omitempty"`
+}
+
+// GetAction returns the Action field if it's non-nil, zero value otherwise.
+func (e *ClassicProjectCardEvent) GetAction() string {
+	if e == nil || e.Action == nil {
+		return ""
+	}
+	return *e.Action
+}
+
+// GetChanges returns the Changes field.
+func (e *ClassicProjectCardEvent) GetChanges() *github.ProjectCardChange {
+	if e == nil {
+		return nil
+	}
+	return e.Changes
+}
+
+// GetAfterID returns the AfterID field if it's non-nil, zero value otherwise.
+func (e *ClassicProjectCardEvent) GetAfterID() int64 {
+	if e == nil || e.AfterID == nil {
+		return 0
+	}
+	return *e.AfterID
+}
+
+// GetProjectCard returns the ProjectCard field.
+func (e *ClassicProjectCardEvent) GetProjectCard() *ClassicProjectCard {
+	if e == nil {
+		return nil
+	}
+	return e.ProjectCard
+}
+
+// GetRepository returns the Repository field.
+func (e *ClassicProjectCardEvent) GetRepository() *github.Repository {
+	if e == nil {
+		return nil
+	}
+	return e.Repository
+}
+
+// GetOrganization returns the Organization field.
+func (e *ClassicProjectCardEvent) GetOrganization() *github.Organization {
+	if e == nil {
+		return nil
+	}
+	return e.Organization
+}
+
+// GetSender returns the Sender field.
+func (e *ClassicProjectCardEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+// GetInstallation returns the Installation field.
+func (e *ClassicProjectCardEvent) GetInstallation() *github.Installation {
+	if e == nil {
+		return nil
+	}
+	return e.Installation
+}
+
+// ClassicProjectColumnEvent Triggered when a column of a classic project is created, updated, moved, or deleted (event: project_column).
+//
+// GitHub API docs: https://docs.github.com/en/webhooks/webhook-events-and-payloads#project_column
+type ClassicProjectColumnEvent struct {
+	Action        *string               `json:"action,omitempty"`
+	Changes       json.RawMessage       `json:"changes,omitempty"`
+	AfterID       *int64                `json:"after_id,omitempty"`
+	ProjectColumn *ClassicProjectColumn `json:"project_column,omitempty"`
+
+	Repository   *github.Repository   `json:"repository,omitempty"`
+	Organization *github.Organization `json:"organization,omitempty"`
+	Sender       *github.User         `json:"sender,omitempty"`
+	Installation *github.Installation `json:"installation,omitempty"`
+}
+
+// GetAction returns the Action field if it's non-nil, zero value otherwise.
+func (e *ClassicProjectColumnEvent) GetAction() string {
+	if e == nil || e.Action == nil {
+		return ""
+	}
+	return *e.Action
+}
+
+// GetAfterID returns the AfterID field if it's non-nil, zero value otherwise.
+func (e *ClassicProjectColumnEvent) GetAfterID() int64 {
+	if e == nil || e.AfterID == nil {
+		return 0
+	}
+	return *e.AfterID
+}
+
+// GetProjectColumn returns the ProjectColumn field.
+func (e *ClassicProjectColumnEvent) GetProjectColumn() *ClassicProjectColumn {
+	if e == nil {
+		return nil
+	}
+	return e.ProjectColumn
+}
+
+// GetRepository returns the Repository field.
+func (e *ClassicProjectColumnEvent) GetRepository() *github.Repository {
+	if e == nil {
+		return nil
+	}
+	return e.Repository
+}
+
+// GetOrganization returns the Organization field.
+func (e *ClassicProjectColumnEvent) GetOrganization() *github.Organization {
+	if e == nil {
+		return nil
+	}
+	return e.Organization
+}
+
+// GetSender returns the Sender field.
+func (e *ClassicProjectColumnEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+// GetInstallation returns the Installation field.
+func (e *ClassicProjectColumnEvent) GetInstallation() *github.Installation {
+	if e == nil {
+		return nil
+	}
+	return e.Installation
+}
+
+// RegistryPackageEvent Triggered when a package is published or updated in GitHub Packages (event: registry_package).
+//
+// GitHub API docs: https://docs.github.com/en/webhooks/webhook-events-and-payloads#registry_package
+type RegistryPackageEvent struct {
+	Action          *string         `json:"action,omitempty"`
+	RegistryPackage *github.Package `json:"registry_package,omitempty"`
+
+	Repository   *github.Repository   `json:"repository,omitempty"`
+	Organization *github.Organization `json:"organization,omitempty"`
+	Sender       *github.User         `json:"sender,omitempty"`
+	Installation *github.Installation `json:"installation,omitempty"`
+}
+
+// GetAction returns the Action field if it's non-nil, zero value otherwise.
+func (e *RegistryPackageEvent) GetAction() string {
+	if e == nil || e.Action == nil {
+		return ""
+	}
+	return *e.Action
+}
+
+// GetRegistryPackage returns the RegistryPackage field.
+func (e *RegistryPackageEvent) GetRegistryPackage() *github.Package {
+	if e == nil {
+		return nil
+	}
+	return e.RegistryPackage
+}
+
+// GetRepository returns the Repository field.
+func (e *RegistryPackageEvent) GetRepository() *github.Repository {
+	if e == nil {
+		return nil
+	}
+	return e.Repository
+}
+
+// GetOrganization returns the Organization field.
+func (e *RegistryPackageEvent) GetOrganization() *github.Organization {
+	if e == nil {
+		return nil
+	}
+	return e.Organization
+}
+
+// GetSender returns the Sender field.
+func (e *RegistryPackageEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+// GetInstallation returns the Installation field.
+func (e *RegistryPackageEvent) GetInstallation() *github.Installation {
+	if e == nil {
+		return nil
+	}
+	return e.Installation
+}
+
+// RepositoryAdvisoryEvent Triggered when a repository security advisory is published or reported (event: repository_advisory).
+//
+// GitHub API docs: https://docs.github.com/en/webhooks/webhook-events-and-payloads#repository_advisory
+type RepositoryAdvisoryEvent struct {
+	Action             *string                  `json:"action,omitempty"`
+	RepositoryAdvisory *github.SecurityAdvisory `json:"repository_advisory,omitempty"`
+
+	Repository   *github.Repository   `json:"repository,omitempty"`
+	Organization *github.Organization `json:"organization,omitempty"`
+	Sender       *github.User         `json:"sender,omitempty"`
+	Installation *github.Installation `json:"installation,omitempty"`
+}
+
+// GetAction returns the Action field if it's non-nil, zero value otherwise.
+func (e *RepositoryAdvisoryEvent) GetAction() string {
+	if e == nil || e.Action == nil {
+		return ""
+	}
+	return *e.Action
+}
+
+// GetRepositoryAdvisory returns the RepositoryAdvisory field.
+func (e *RepositoryAdvisoryEvent) GetRepositoryAdvisory() *github.SecurityAdvisory {
+	if e == nil {
+		return nil
+	}
+	return e.RepositoryAdvisory
+}
+
+// GetRepository returns the Repository field.
+func (e *RepositoryAdvisoryEvent) GetRepository() *github.Repository {
+	if e == nil {
+		return nil
+	}
+	return e.Repository
+}
+
+// GetOrganization returns the Organization field.
+func (e *RepositoryAdvisoryEvent) GetOrganization() *github.Organization {
+	if e == nil {
+		return nil
+	}
+	return e.Organization
+}
+
+// GetSender returns the Sender field.
+func (e *RepositoryAdvisoryEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+// GetInstallation returns the Installation field.
+func (e *RepositoryAdvisoryEvent) GetInstallation() *github.Installation {
+	if e == nil {
+		return nil
+	}
+	return e.Installation
+}
+
+// ClassicProject A classic project.
+type ClassicProject struct {
+	ID        *int64            `json:"id,omitempty"`
+	NodeID    *string           `json:"node_id,omitempty"`
+	Number    *int              `json:"number,omitempty"`
+	Name      *string           `json:"name,omitempty"`
+	Body      *string           `json:"body,omitempty"`
+	State     *string           `json:"state,omitempty"`
+	HTMLURL   *string           `json:"html_url,omitempty"`
+	Creator   *github.User      `json:"creator,omitempty"`
+	CreatedAt *github.Timestamp `json:"created_at,omitempty"`
+	UpdatedAt *github.Timestamp `json:"updated_at,omitempty"`
+}
+
+// GetID returns the ID field if it's non-nil, zero value otherwise.
+func (e *ClassicProject) GetID() int64 {
+	if e == nil || e.ID == nil {
+		return 0
+	}
+	return *e.ID
+}
+
+// GetNodeID returns the NodeID field if it's non-nil, zero value otherwise.
+func (e *ClassicProject) GetNodeID() string {
+	if e == nil || e.NodeID == nil {
+		return ""
+	}
+	return *e.NodeID
+}
+
+// GetNumber returns the Number field if it's non-nil, zero value otherwise.
+func (e *ClassicProject) GetNumber() int {
+	if e == nil || e.Number == nil {
+		return 0
+	}
+	return *e.Number
+}
+
+// GetName returns the Name field if it's non-nil, zero value otherwise.
+func (e *ClassicProject) GetName() string {
+	if e == nil || e.Name == nil {
+		return ""
+	}
+	return *e.Name
+}
+
+// GetBody returns the Body field if it's non-nil, zero value otherwise.
+func (e *ClassicProject) GetBody() string {
+	if e == nil || e.Body == nil {
+		return ""
+	}
+	return *e.Body
+}
+
+// GetState returns the State field if it's non-nil, zero value otherwise.
+func (e *ClassicProject) GetState() string {
+	if e == nil || e.State == nil {
+		return ""
+	}
+	return *e.State
+}
+
+// GetHTMLURL returns the HTMLURL field if it's non-nil, zero value otherwise.
+func (e *ClassicProject) GetHTMLURL() string {
+	if e == nil || e.HTMLURL == nil {
+		return ""
+	}
+	return *e.HTMLURL
+}
+
+// GetCreator returns the Creator field.
+func (e *ClassicProject) GetCreator() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Creator
+}
+
+// GetCreatedAt returns the CreatedAt field.
+func (e *ClassicProject) GetCreatedAt() *github.Timestamp {
+	if e == nil {
+		return nil
+	}
+	return e.CreatedAt
+}
+
+// GetUpdatedAt returns the UpdatedAt field.
+func (e *ClassicProject) GetUpdatedAt() *github.Timestamp {
+	if e == nil {
+		return nil
+	}
+	return e.UpdatedAt
+}
+
+// ClassicProjectCard A card of a classic project.
+type ClassicProjectCard struct {
+	ID         *int64            `json:"id,omitempty"`
+	NodeID     *string           `json:"node_id,omitempty"`
+	Note       *string           `json:"note,omitempty"`
+	Archived   *bool             `json:"archived,omitempty"`
+	ColumnID   *int64            `json:"column_id,omitempty"`
+	ContentURL *string           `json:"content_url,omitempty"`
+	Creator    *github.User      `json:"creator,omitempty"`
+	CreatedAt  *github.Timestamp `json:"created_at,omitempty"`
+	UpdatedAt  *github.Timestamp `json:"updated_at,omitempty"`
+}
+
+// GetID returns the ID field if it's non-nil, zero value otherwise.
+func (e *ClassicProjectCard) GetID() int64 {
+	if e == nil || e.ID == nil {
+		return 0
+	}
+	return *e.ID
+}
+
+// GetNodeID returns the NodeID field if it's non-nil, zero value otherwise.
+func (e *ClassicProjectCard) GetNodeID() string {
+	if e == nil || e.NodeID == nil {
+		return ""
+	}
+	return *e.NodeID
+}
+
+// GetNote returns the Note field if it's non-nil, zero value otherwise.
+func (e *ClassicProjectCard) GetNote() string {
+	if e == nil || e.Note == nil {
+		return ""
+	}
+	return *e.Note
+}
+
+// GetArchived returns the Archived field if it's non-nil, zero value otherwise.
+func (e *ClassicProjectCard) GetArchived() bool {
+	if e == nil || e.Archived == nil {
+		return false
+	}
+	return *e.Archived
+}
+
+// GetColumnID returns the ColumnID field if it's non-nil, zero value otherwise.
+func (e *ClassicProjectCard) GetColumnID() int64 {
+	if e == nil || e.ColumnID == nil {
+		return 0
+	}
+	return *e.ColumnID
+}
+
+// GetContentURL returns the ContentURL field if it's non-nil, zero value otherwise.
+func (e *ClassicProjectCard) GetContentURL() string {
+	if e == nil || e.ContentURL == nil {
+		return ""
+	}
+	return *e.ContentURL
+}
+
+// GetCreator returns the Creator field.
+func (e *ClassicProjectCard) GetCreator() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Creator
+}
+
+// GetCreatedAt returns the CreatedAt field.
+func (e *ClassicProjectCard) GetCreatedAt() *github.Timestamp {
+	if e == nil {
+		return nil
+	}
+	return e.CreatedAt
+}
+
+// GetUpdatedAt returns the UpdatedAt field.
+func (e *ClassicProjectCard) GetUpdatedAt() *github.Timestamp {
+	if e == nil {
+		return nil
+	}
+	return e.UpdatedAt
+}
+
+// ClassicProjectColumn A column of a classic project.
+type ClassicProjectColumn struct {
+	ID         *int64            `json:"id,omitempty"`
+	NodeID     *string           `json:"node_id,omitempty"`
+	Name       *string           `json:"name,omitempty"`
+	ProjectURL *string           `json:"project_url,omitempty"`
+	CreatedAt  *github.Timestamp `json:"created_at,omitempty"`
+	UpdatedAt  *github.Timestamp `json:"updated_at,omitempty"`
+}
+
+// GetID returns the ID field if it's non-nil, zero value otherwise.
+func (e *ClassicProjectColumn) GetID() int64 {
+	if e == nil || e.ID == nil {
+		return 0
+	}
+	return *e.ID
+}
+
+// GetNodeID returns the NodeID field if it's non-nil, zero value otherwise.
+func (e *ClassicProjectColumn) GetNodeID() string {
+	if e == nil || e.NodeID == nil {
+		return ""
+	}
+	return *e.NodeID
+}
+
+// GetName returns the Name field if it's non-nil, zero value otherwise.
+func (e *ClassicProjectColumn) GetName() string {
+	if e == nil || e.Name == nil {
+		return ""
+	}
+	return *e.Name
+}
+
+// GetProjectURL returns the ProjectURL field if it's non-nil, zero value otherwise.
+func (e *ClassicProjectColumn) GetProjectURL() string {
+	if e == nil || e.ProjectURL == nil {
+		return ""
+	}
+	return *e.ProjectURL
+}
+
+// GetCreatedAt returns the CreatedAt field.
+func (e *ClassicProjectColumn) GetCreatedAt() *github.Timestamp {
+	if e == nil {
+		return nil
+	}
+	return e.CreatedAt
+}
+
+// GetUpdatedAt returns the UpdatedAt field.
+func (e *ClassicProjectColumn) GetUpdatedAt() *github.Timestamp {
+	if e == nil {
+		return nil
+	}
+	return e.UpdatedAt
+}
@@ -0,0 +1,309 @@
+// Package exec Runs subprocesses inside an action:
+// the output is streamed to the log (optionally inside a group), captured, and matched by the problem matchers.
+package exec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	osexec "os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ldez/ghactions"
+)
+
+// waitDelay the delay before closing the output pipes after the cancellation of the context.
+const waitDelay = 5 * time.Second
+
+// Option An option of Run.
+type Option func(*config)
+
+type config struct {
+	dir      string
+	env      map[string]string
+	stdin    io.Reader
+	stdout   io.Writer
+	stderr   io.Writer
+	group    string
+	matchers []*ghactions.Matcher
+}
+
+// WithDir Defines the working directory of the command (the current directory by default).
+func WithDir(dir string) Option {
+	return func(cfg *config) {
+		cfg.dir = dir
+	}
+}
+
+// WithEnv Adds environment variables to the environment of the current process (the values override the environment).
+func WithEnv(env map[string]string) Option {
+	return func(cfg *config) {
+		for key, value := range env {
+			cfg.env[key] = value
+		}
+	}
+}
+
+// WithStdin Defines the standard input of the command.
+func WithStdin(stdin io.Reader) Option {
+	return func(cfg *config) {
+		cfg.stdin = stdin
+	}
+}
+
+// WithOutput Defines where the output of the command is streamed (os.Stdout and os.Stderr by default).
+// The nil writers disable the streaming, the output is still captured.
+func WithOutput(stdout, stderr io.Writer) Option {
+	return func(cfg *config) {
+		cfg.stdout = stdout
+		cfg.stderr = stderr
+	}
+}
+
+// WithSilent Disables the streaming of the output, the output is still captured.
+func WithSilent() Option {
+	return WithOutput(nil, nil)
+}
+
+// WithGroup Streams the output inside a collapsible group of the log.
+func WithGroup(title string) Option {
+	return func(cfg *config) {
+		cfg.group = title
+	}
+}
+
+// WithMatchers Matches the lines of the output (stdout and stderr) with problem matchers,
+// and creates the annotations of the problems (see Result.Problems).
+func WithMatchers(matchers ...*ghactions.Matcher) Option {
+	return func(cfg *config) {
+		cfg.matchers = append(cfg.matchers, matchers...)
+	}
+}
+
+// Result The result of a command.
+type Result struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	// Problems The problems found by the matchers (WithMatchers).
+	Problems []ghactions.Problem
+}
+
+// ExitError The command exited with a non-zero exit code.
+type ExitError struct {
+	Command  string
+	ExitCode int
+	Stderr   string
+
+	err error
+}
+
+func (e *ExitError) Error() string {
+	msg := fmt.Sprintf("%s: exit code %d", e.Command, e.ExitCode)
+
+	if stderr := strings.TrimSpace(e.Stderr); stderr != "" {
+		lines := strings.Split(stderr, "\n")
+		msg += ": " + lines[len(lines)-1]
+	}
+
+	return msg
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.err
+}
+
+// Run Runs a command, and returns its result.
+// Returns an ExitError (with the result) when the command exits with a non-zero exit code.
+// The command is killed when the context is canceled.
+func Run(ctx context.Context, name string, args []string, opts ...Option) (*Result, error) {
+	cfg := &config{env: map[string]string{}, stdout: os.Stdout, stderr: os.Stderr}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	command := strings.Join(append([]string{name}, args...), " ")
+
+	cmd := osexec.CommandContext(ctx, name, args...)
+	cmd.Dir = cfg.dir
+	cmd.Stdin = cfg.stdin
+	cmd.WaitDelay = waitDelay
+
+	if len(cfg.env) > 0 {
+		cmd.Env = mergeEnv(os.Environ(), cfg.env)
+	}
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	problems := &problemCollector{}
+
+	// the state of the multi-line problems is not shared by stdout and stderr (like the runner).
+	// The lines are written to the stream before their annotations.
+	outLines := newLineWriter(orDiscard(cfg.stdout), cfg.matchers, problems)
+	errLines := newLineWriter(orDiscard(cfg.stderr), cfg.matchers, problems)
+
+	cmd.Stdout = io.MultiWriter(stdout, outLines)
+	cmd.Stderr = io.MultiWriter(stderr, errLines)
+
+	if cfg.group != "" {
+		ghactions.StartGroup(cfg.group)
+	}
+
+	err := cmd.Run()
+
+	outLines.flush()
+	errLines.flush()
+
+	if cfg.group != "" {
+		ghactions.EndGroup()
+	}
+
+	result := &Result{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		ExitCode: cmd.ProcessState.ExitCode(),
+		Problems: problems.problems,
+	}
+
+	if ctx.Err() != nil {
+		return result, fmt.Errorf("%s: %w", command, context.Cause(ctx))
+	}
+
+	var exitErr *osexec.ExitError
+	if errors.As(err, &exitErr) {
+		return result, &ExitError{Command: command, ExitCode: exitErr.ExitCode(), Stderr: result.Stderr, err: err}
+	}
+
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", command, err)
+	}
+
+	return result, nil
+}
+
+// Output Runs a command silently, and returns its standard output without the leading and trailing spaces.
+func Output(ctx context.Context, name string, args []string, opts ...Option) (string, error) {
+	result, err := Run(ctx, name, args, append(opts, WithSilent())...)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(result.Stdout), nil
+}
+
+// mergeEnv overrides the variables of environ with env.
+func mergeEnv(environ []string, env map[string]string) []string {
+	merged := make([]string, 0, len(environ)+len(env))
+
+	for _, kv := range environ {
+		key, _, _ := strings.Cut(kv, "=")
+		if _, ok := env[key]; !ok {
+			merged = append(merged, kv)
+		}
+	}
+
+	// sorted: the environment of the command is deterministic.
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		merged = append(merged, key+"="+env[key])
+	}
+
+	return merged
+}
+
+func orDiscard(w io.Writer) io.Writer {
+	if w == nil {
+		return io.Discard
+	}
+
+	return w
+}
+
+// problemCollector annotates and collects the problems of stdout and stderr.
+type problemCollector struct {
+	mu       sync.Mutex
+	problems []ghactions.Problem
+}
+
+func (c *problemCollector) add(problem *ghactions.Problem) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	problem.Annotate()
+
+	c.problems = append(c.problems, *problem)
+}
+
+// lineWriter splits the output of a stream in lines, forwards the lines to the stream,
+// and matches the lines with the problem matchers of the stream.
+// With matchers, only the complete lines are forwarded: an annotation is written after its line, at a line boundary.
+type lineWriter struct {
+	out      io.Writer
+	matchers []*ghactions.Matcher
+	problems *problemCollector
+	buf      []byte
+}
+
+// newLineWriter creates a line writer with its own copy of the matchers.
+func newLineWriter(out io.Writer, matchers []*ghactions.Matcher, problems *problemCollector) *lineWriter {
+	w := &lineWriter{out: out, problems: problems}
+
+	for _, m := range matchers {
+		w.matchers = append(w.matchers, m.Clone())
+	}
+
+	return w
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	if len(w.matchers) == 0 {
+		return w.out.Write(p)
+	}
+
+	w.buf = append(w.buf, p...)
+
+	for {
+		idx := bytes.IndexByte(w.buf, '\n')
+		if idx < 0 {
+			break
+		}
+
+		_, err := w.out.Write(w.buf[:idx+1])
+		if err != nil {
+			return 0, err
+		}
+
+		w.match(string(w.buf[:idx]))
+
+		w.buf = w.buf[idx+1:]
+	}
+
+	return len(p), nil
+}
+
+func (w *lineWriter) match(line string) {
+	for _, m := range w.matchers {
+		if problem, ok := m.Match(line); ok {
+			w.problems.add(problem)
+		}
+	}
+}
+
+// flush forwards and matches the last line (without a trailing new line).
+func (w *lineWriter) flush() {
+	if len(w.buf) == 0 {
+		return
+	}
+
+	// ends the line: the annotation must start on its own line.
+	_, _ = w.out.Write(append(w.buf, '\n'))
+
+	w.match(string(w.buf))
+	w.buf = nil
+}
@@ -0,0 +1,224 @@
+package exec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ldez/ghactions"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Setenv("GHACTIONS_TEST_KEEP", "keep")
+	t.Setenv("GHACTIONS_TEST_OVERRIDE", "old")
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	script := `echo "$GHACTIONS_TEST_KEEP $GHACTIONS_TEST_OVERRIDE $GHACTIONS_TEST_NEW"; pwd; cat; echo oops >&2`
+
+	result, err := Run(context.Background(), "sh", []string{"-c", script},
+		WithDir(dir),
+		WithEnv(map[string]string{"GHACTIONS_TEST_OVERRIDE": "new", "GHACTIONS_TEST_NEW": "added"}),
+		WithStdin(strings.NewReader("input\n")),
+		WithOutput(stdout, stderr),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "keep new added\n" + realDir + "\ninput\n"
+
+	if result.Stdout != expected || stdout.String() != expected {
+		t.Errorf("got stdout %q (streamed %q), want %q", result.Stdout, stdout.String(), expected)
+	}
+
+	if result.Stderr != "oops\n" || stderr.String() != "oops\n" {
+		t.Errorf("got stderr %q (streamed %q)", result.Stderr, stderr.String())
+	}
+
+	if result.ExitCode != 0 {
+		t.Errorf("got exit code %d", result.ExitCode)
+	}
+}
+
+func TestRun_exitError(t *testing.T) {
+	result, err := Run(context.Background(), "sh", []string{"-c", "echo first >&2; echo failed >&2; exit 3"}, WithSilent())
+
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("got %v, want an ExitError", err)
+	}
+
+	if exitErr.ExitCode != 3 || result.ExitCode != 3 {
+		t.Errorf("got exit code %d (result %d), want 3", exitErr.ExitCode, result.ExitCode)
+	}
+
+	if !strings.HasSuffix(err.Error(), ": exit code 3: failed") {
+		t.Errorf("got %q", err)
+	}
+}
+
+func TestRun_contextCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	_, err := Run(ctx, "sleep", []string{"10"}, WithSilent())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want context.DeadlineExceeded", err)
+	}
+
+	if time.Since(start) > 5*time.Second {
+		t.Error("the command was not killed")
+	}
+}
+
+func TestRun_notFound(t *testing.T) {
+	_, err := Run(context.Background(), "ghactions-unknown-command", nil, WithSilent())
+	if err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestRun_matchers(t *testing.T) {
+	t.Setenv(ghactions.GithubWorkspace, "/ws")
+
+	matcher, err := ghactions.NewMatcher(ghactions.ProblemMatcher{
+		Owner: "lint",
+		Pattern: []ghactions.ProblemPattern{{
+			Regexp:  `^(.+):(\d+): (.+)$`,
+			File:    1,
+			Line:    2,
+			Message: 3,
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	script := `echo "/ws/a.go:1: first"; echo "noise"; printf "b.go:2: last" >&2`
+
+	result, err := Run(context.Background(), "sh", []string{"-c", script}, WithSilent(), WithMatchers(matcher))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ghactions.Problem{
+		{Level: ghactions.AnnotationError, Message: "first", Annotation: ghactions.Annotation{File: "a.go", Line: 1}},
+		{Level: ghactions.AnnotationError, Message: "last", Annotation: ghactions.Annotation{File: "b.go", Line: 2}},
+	}
+
+	if len(result.Problems) != len(expected) {
+		t.Fatalf("got %+v, want %+v", result.Problems, expected)
+	}
+
+	for i, problem := range result.Problems {
+		if problem != expected[i] {
+			t.Errorf("problem %d: got %+v, want %+v", i, problem, expected[i])
+		}
+	}
+}
+
+// TestRun_matchers_streams checks that the lines of stderr do not break a multi-line problem of stdout.
+func TestRun_matchers_streams(t *testing.T) {
+	matcher, err := ghactions.NewMatcher(ghactions.ProblemMatcher{
+		Owner: "two-lines",
+		Pattern: []ghactions.ProblemPattern{
+			{Regexp: `^FILE (.+)$`, File: 1},
+			{Regexp: `^MSG (.+)$`, Message: 1},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	script := `echo "FILE a.txt"; sleep 0.1; echo "noise" >&2; sleep 0.1; echo "MSG first"`
+
+	result, err := Run(context.Background(), "sh", []string{"-c", script}, WithSilent(), WithMatchers(matcher))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := ghactions.Problem{Level: ghactions.AnnotationError, Message: "first", Annotation: ghactions.Annotation{File: "a.txt"}}
+
+	if len(result.Problems) != 1 || result.Problems[0] != expected {
+		t.Errorf("got %+v, want %+v", result.Problems, expected)
+	}
+}
+
+// Test_lineWriter checks that only the complete lines are forwarded to the stream, before their annotations.
+func Test_lineWriter(t *testing.T) {
+	matcher, err := ghactions.NewMatcher(ghactions.ProblemMatcher{
+		Owner: "lint",
+		Pattern: []ghactions.ProblemPattern{{
+			Regexp:  `^(.+):(\d+): (.+)$`,
+			File:    1,
+			Line:    2,
+			Message: 3,
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+	problems := &problemCollector{}
+
+	w := newLineWriter(out, []*ghactions.Matcher{matcher}, problems)
+
+	steps := []struct {
+		write    string
+		expected string
+		problems int
+	}{
+		{write: "a.go:1: fir", expected: "", problems: 0},
+		{write: "st\nb.go:2: sec", expected: "a.go:1: first\n", problems: 1},
+		{write: "ond\nnoise\n", expected: "a.go:1: first\nb.go:2: second\nnoise\n", problems: 2},
+	}
+
+	for _, step := range steps {
+		_, err = w.Write([]byte(step.write))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if out.String() != step.expected || len(problems.problems) != step.problems {
+			t.Errorf("after %q: got %q (%d problems), want %q (%d problems)", step.write, out.String(), len(problems.problems), step.expected, step.problems)
+		}
+	}
+
+	_, _ = w.Write([]byte("c.go:3: last"))
+	w.flush()
+
+	if !strings.HasSuffix(out.String(), "c.go:3: last\n") || len(problems.problems) != 3 {
+		t.Errorf("got %q (%d problems) after the flush", out.String(), len(problems.problems))
+	}
+}
+
+func Test_mergeEnv(t *testing.T) {
+	merged := mergeEnv([]string{"A=1", "B=2", "C=3=3"}, map[string]string{"B": "20", "D": "4"})
+
+	expected := []string{"A=1", "C=3=3", "B=20", "D=4"}
+
+	for range 10 {
+		if got := mergeEnv([]string{"A=1"}, map[string]string{"Z": "1", "M": "2", "B": "3"}); strings.Join(got, " ") != "A=1 B=3 M=2 Z=1" {
+			t.Fatalf("got %v, want sorted variables", got)
+		}
+	}
+
+	if strings.Join(merged, " ") != strings.Join(expected, " ") {
+		t.Errorf("got %v, want %v", merged, expected)
+	}
+}
@@ -0,0 +1,130 @@
+package ghactions
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// InputContinueOnError the input allowing the action to succeed when it fails.
+const InputContinueOnError = "continue-on-error"
+
+// NeutralError The action ends without success nor failure: Main exits with 0 and creates a notice.
+type NeutralError struct {
+	Reason string
+}
+
+// Neutral Creates a NeutralError.
+func Neutral(reason string) error {
+	return &NeutralError{Reason: reason}
+}
+
+func (e *NeutralError) Error() string {
+	return "neutral: " + e.Reason
+}
+
+// SkipError The action has nothing to do: Main exits with 0 and creates a notice.
+type SkipError struct {
+	Reason string
+}
+
+// Skip Creates a SkipError.
+func Skip(reason string) error {
+	return &SkipError{Reason: reason}
+}
+
+func (e *SkipError) Error() string {
+	return "skipped: " + e.Reason
+}
+
+// Main Runs the action and exits:
+//   - 0 when the action succeeds, or when the handler returns Neutral or Skip (a notice is created).
+//   - 1 when the action fails: an error annotation and an entry of the step summary are created.
+//     When the input continue-on-error is true, a warning is created and the exit code is 0.
+//
+// The pending outputs and summary are written before exiting (see Flush).
+func Main(action *Action) {
+	os.Exit(run(action))
+}
+
+// run runs the action and returns the exit code.
+func run(action *Action) int {
+	err := action.Run()
+
+	// the annotations of the result are not inside the group of the log.
+	closeLogGroup()
+
+	code := exitCode(err)
+
+	err = Flush()
+	if err != nil {
+		Annotate(AnnotationError, fmt.Sprintf("write the outputs and the summary: %v", err), Annotation{})
+
+		return 1
+	}
+
+	return code
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var neutral *NeutralError
+	if errors.As(err, &neutral) {
+		Annotate(AnnotationNotice, neutral.Reason, Annotation{Title: "Neutral"})
+		return 0
+	}
+
+	var skip *SkipError
+	if errors.As(err, &skip) {
+		Annotate(AnnotationNotice, skip.Reason, Annotation{Title: "Skipped"})
+		return 0
+	}
+
+	if continueOnError() {
+		Annotate(AnnotationWarning, err.Error(), Annotation{Title: "Failure ignored (" + InputContinueOnError + ")"})
+		return 0
+	}
+
+	if !annotated(err) {
+		Annotate(AnnotationError, err.Error(), Annotation{Title: "Failure"})
+	}
+
+	AddSummary(fmt.Sprintf("### :x: %s failed\n\n```\n%s\n```\n", actionName(), err))
+
+	return 1
+}
+
+// annotated checks if an annotation has already been created for the error.
+func annotated(err error) bool {
+	var perr *PanicError
+
+	return errors.As(err, &perr) || errors.Is(err, ErrActorRefused) || errors.Is(err, ErrUnsafePullRequestTarget)
+}
+
+func continueOnError() bool {
+	value, err := strconv.ParseBool(strings.TrimSpace(GetInput(InputContinueOnError)))
+	return err == nil && value
+}
+
+func actionName() string {
+	if name := os.Getenv(GithubAction); name != "" {
+		return name
+	}
+
+	return "The action"
+}
+
+// GetInput Returns the value of an input of the action (INPUT_<NAME>).
+func GetInput(name string) string {
+	return os.Getenv(inputEnvName(name))
+}
+
+// inputEnvName returns the environment variable of an input of the action, like the runner.
+func inputEnvName(name string) string {
+	return "INPUT_" + strings.ToUpper(strings.ReplaceAll(name, " ", "_"))
+}
@@ -0,0 +1,95 @@
+package ghactions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v71/github"
+)
+
+func Test_run(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		err             error
+		continueOnError string
+		expectedCode    int
+		expectedOutput  string
+		expectedSummary string
+	}{
+		{
+			desc:           "success",
+			expectedOutput: "",
+		},
+		{
+			desc:           "neutral",
+			err:            Neutral("nothing to release"),
+			expectedOutput: "::notice title=Neutral::nothing to release\n",
+		},
+		{
+			desc:           "skip",
+			err:            fmt.Errorf("wrapped: %w", Skip("draft pull request")),
+			expectedOutput: "::notice title=Skipped::draft pull request\n",
+		},
+		{
+			desc:            "failure",
+			err:             errors.New("boom"),
+			expectedCode:    1,
+			expectedOutput:  "::error title=Failure::boom\n",
+			expectedSummary: "### :x: my-action failed\n\n```\nboom\n```\n",
+		},
+		{
+			desc:            "continue on error",
+			err:             errors.New("boom"),
+			continueOnError: "true",
+			expectedOutput:  "::warning title=Failure ignored (continue-on-error)::boom\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			buf := captureCommands(t)
+
+			summaryPath := filepath.Join(t.TempDir(), "summary")
+			outputPath := filepath.Join(t.TempDir(), "output")
+
+			t.Setenv(GithubStepSummary, summaryPath)
+			t.Setenv(GithubOutput, outputPath)
+			t.Setenv(GithubAction, "my-action")
+			t.Setenv("INPUT_CONTINUE-ON-ERROR", test.continueOnError)
+
+			writeEvent(t, "push", `{"ref":"refs/heads/main"}`)
+
+			action := NewAction(context.Background()).
+				OnPush(func(*github.Client, *github.PushEvent) error {
+					SetOutput("result", "done")
+					return test.err
+				})
+
+			action.Logger = discardLogger()
+
+			code := run(action)
+			if code != test.expectedCode {
+				t.Errorf("got exit code %d, want %d", code, test.expectedCode)
+			}
+
+			if buf.String() != test.expectedOutput {
+				t.Errorf("got %q, want %q", buf.String(), test.expectedOutput)
+			}
+
+			summary, _ := os.ReadFile(summaryPath)
+			if string(summary) != test.expectedSummary {
+				t.Errorf("got summary %q, want %q", summary, test.expectedSummary)
+			}
+
+			output, _ := os.ReadFile(outputPath)
+			if !strings.HasPrefix(string(output), "result<<") {
+				t.Errorf("the outputs must be flushed: %q", output)
+			}
+		})
+	}
+}
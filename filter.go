@@ -0,0 +1,242 @@
+package ghactions
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v71/github"
+)
+
+// Filter A list of filter patterns, with the semantics of the workflow filters (branches, tags, paths).
+//   - `*` matches zero or more characters, but not `/`.
+//   - `**` matches zero or more of any character.
+//   - `?` matches zero or one of the preceding character.
+//   - `+` matches one or more of the preceding character.
+//   - `[]` matches one character listed in the brackets or included in ranges.
+//   - `!` at the start of a pattern negates the previous positive patterns.
+//
+// The patterns are evaluated in order: the last matching pattern wins.
+//
+// https://docs.github.com/en/actions/writing-workflows/workflow-syntax-for-github-actions#filter-pattern-cheat-sheet
+type Filter []string
+
+// Match Checks if a value is matched by the filter:
+// the last pattern that matches the value must not be a negative pattern.
+func (f Filter) Match(value string) bool {
+	var matched bool
+
+	for _, pattern := range f {
+		negative := strings.HasPrefix(pattern, "!")
+		if negative {
+			pattern = pattern[1:]
+		}
+
+		if matchPattern(pattern, value) {
+			matched = !negative
+		}
+	}
+
+	return matched
+}
+
+// Includes Checks if a value is selected by an include filter (branches, tags, paths):
+// an empty filter selects everything.
+func (f Filter) Includes(value string) bool {
+	return len(f) == 0 || f.Match(value)
+}
+
+// Excludes Checks if a value is rejected by an ignore filter (branches-ignore, tags-ignore, paths-ignore):
+// an empty filter rejects nothing.
+func (f Filter) Excludes(value string) bool {
+	return len(f) > 0 && f.Match(value)
+}
+
+var patterns sync.Map // pattern -> *regexp.Regexp
+
+func matchPattern(pattern, value string) bool {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp).MatchString(value)
+	}
+
+	re, err := regexp.Compile(patternToRegexp(pattern))
+	if err != nil {
+		// an invalid pattern (ex: an unclosed bracket) is matched literally.
+		re = regexp.MustCompile("^" + regexp.QuoteMeta(pattern) + "$")
+	}
+
+	patterns.Store(pattern, re)
+
+	return re.MatchString(value)
+}
+
+func patternToRegexp(pattern string) string {
+	var b strings.Builder
+
+	b.WriteString("^")
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				b.WriteString(".*")
+				i++
+			} else {
+				b.WriteString("[^/]*")
+			}
+
+		case '?', '+':
+			b.WriteByte(c)
+
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				b.WriteString(regexp.QuoteMeta(pattern[i:]))
+				i = len(pattern)
+
+				continue
+			}
+
+			b.WriteString(pattern[i : i+end+1])
+			i += end
+
+		case '\\':
+			// escapes the next character.
+			if i+1 < len(pattern) {
+				b.WriteString(regexp.QuoteMeta(pattern[i+1 : i+2]))
+				i++
+			}
+
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	b.WriteString("$")
+
+	return b.String()
+}
+
+// FilterOption A branch or tag filter of a handler (push, pull_request, pull_request_target, create).
+type FilterOption func(*refFilters)
+
+// Branches Runs the handler only for the branches matching the patterns.
+// For pull_request and pull_request_target, the patterns are applied on the base branch.
+func Branches(patterns ...string) FilterOption {
+	return func(f *refFilters) {
+		f.branches = append(f.branches, patterns...)
+	}
+}
+
+// BranchesIgnore Skips the handler for the branches matching the patterns.
+func BranchesIgnore(patterns ...string) FilterOption {
+	return func(f *refFilters) {
+		f.branchesIgnore = append(f.branchesIgnore, patterns...)
+	}
+}
+
+// Tags Runs the handler only for the tags matching the patterns.
+func Tags(patterns ...string) FilterOption {
+	return func(f *refFilters) {
+		f.tags = append(f.tags, patterns...)
+	}
+}
+
+// TagsIgnore Skips the handler for the tags matching the patterns.
+func TagsIgnore(patterns ...string) FilterOption {
+	return func(f *refFilters) {
+		f.tagsIgnore = append(f.tagsIgnore, patterns...)
+	}
+}
+
+// refFilters the branch and tag filters of a handler.
+type refFilters struct {
+	branches       Filter
+	branchesIgnore Filter
+	tags           Filter
+	tagsIgnore     Filter
+}
+
+func (a *Action) setFilters(eventName string, options []FilterOption) {
+	if a.filters == nil {
+		a.filters = make(map[string]*refFilters)
+	}
+
+	if len(options) == 0 {
+		delete(a.filters, eventName)
+		return
+	}
+
+	f := &refFilters{}
+	for _, opt := range options {
+		opt(f)
+	}
+
+	a.filters[eventName] = f
+}
+
+// filtered checks the filters of the handler of an event.
+// Returns the reason why the event is skipped, or an empty string.
+func (a *Action) filtered(eventName string, event any) string {
+	f, ok := a.filters[eventName]
+	if !ok {
+		return ""
+	}
+
+	ref := RefFromEvent(event)
+
+	switch evt := event.(type) {
+	case *github.PullRequestEvent:
+		// the filters are applied on the base branch.
+		ref = Ref{Name: refHeads + evt.GetPullRequest().GetBase().GetRef()}
+	case *github.PullRequestTargetEvent:
+		ref = Ref{Name: refHeads + evt.GetPullRequest().GetBase().GetRef()}
+	}
+
+	return f.skip(ref)
+}
+
+// skip applies the filters like a workflow:
+// when only branch filters are defined, the tags are skipped, and vice versa.
+func (f *refFilters) skip(ref Ref) string {
+	hasBranchFilters := len(f.branches) > 0 || len(f.branchesIgnore) > 0
+	hasTagFilters := len(f.tags) > 0 || len(f.tagsIgnore) > 0
+
+	name := ref.ShortName()
+
+	switch {
+	case ref.IsTag():
+		if !hasTagFilters {
+			return fmt.Sprintf("the tag %q is skipped: only branch filters are defined", name)
+		}
+
+		if !f.tags.Includes(name) {
+			return fmt.Sprintf("the tag %q does not match the tags filter %v", name, f.tags)
+		}
+
+		if f.tagsIgnore.Excludes(name) {
+			return fmt.Sprintf("the tag %q matches the tags-ignore filter %v", name, f.tagsIgnore)
+		}
+
+	case ref.IsBranch():
+		if !hasBranchFilters {
+			return fmt.Sprintf("the branch %q is skipped: only tag filters are defined", name)
+		}
+
+		if !f.branches.Includes(name) {
+			return fmt.Sprintf("the branch %q does not match the branches filter %v", name, f.branches)
+		}
+
+		if f.branchesIgnore.Excludes(name) {
+			return fmt.Sprintf("the branch %q matches the branches-ignore filter %v", name, f.branchesIgnore)
+		}
+
+	default:
+		return fmt.Sprintf("the ref %q is neither a branch nor a tag", ref.Name)
+	}
+
+	return ""
+}
@@ -0,0 +1,142 @@
+package ghactions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-github/v71/github"
+)
+
+func TestFilter_Match(t *testing.T) {
+	testCases := []struct {
+		filter   Filter
+		value    string
+		expected bool
+	}{
+		{filter: Filter{"main"}, value: "main", expected: true},
+		{filter: Filter{"main"}, value: "main2", expected: false},
+		{filter: Filter{"feature/*"}, value: "feature/my-branch", expected: true},
+		{filter: Filter{"feature/*"}, value: "feature/your/branch", expected: false},
+		{filter: Filter{"feature/**"}, value: "feature/your/branch", expected: true},
+		{filter: Filter{"v2*"}, value: "v2.0.0", expected: true},
+		{filter: Filter{"v[12].[0-9]+.[0-9]+"}, value: "v1.10.1", expected: true},
+		{filter: Filter{"v[12].[0-9]+.[0-9]+"}, value: "v3.0.0", expected: false},
+		{filter: Filter{"*.jsx?"}, value: "page.js", expected: true},
+		{filter: Filter{"*.jsx?"}, value: "page.jsx", expected: true},
+		{filter: Filter{"*.jsx?"}, value: "page.jsxx", expected: false},
+		{filter: Filter{"**.js"}, value: "js/index.js", expected: true},
+		{filter: Filter{"docs/**", "!docs/internal/**"}, value: "docs/readme.md", expected: true},
+		{filter: Filter{"docs/**", "!docs/internal/**"}, value: "docs/internal/readme.md", expected: false},
+		{filter: Filter{"releases/**", "!releases/**-alpha", "releases/10-alpha"}, value: "releases/10-alpha", expected: true},
+		{filter: Filter{"releases/**", "!releases/**-alpha"}, value: "releases/beta/3-alpha", expected: false},
+	}
+
+	for _, test := range testCases {
+		if test.filter.Match(test.value) != test.expected {
+			t.Errorf("%v: %s: expected %v", test.filter, test.value, test.expected)
+		}
+	}
+}
+
+func TestFilter_IncludesExcludes(t *testing.T) {
+	var empty Filter
+
+	if !empty.Includes("anything") {
+		t.Error("an empty filter must include everything")
+	}
+
+	if empty.Excludes("anything") {
+		t.Error("an empty filter must exclude nothing")
+	}
+}
+
+func TestAction_filters(t *testing.T) {
+	ctx := context.Background()
+
+	testCases := []struct {
+		desc     string
+		filters  []FilterOption
+		payload  string
+		expected bool
+	}{
+		{
+			desc:     "no filters",
+			payload:  `{"ref":"refs/tags/v1.0.0"}`,
+			expected: true,
+		},
+		{
+			desc:     "matching branch",
+			filters:  []FilterOption{Branches("main", "releases/**")},
+			payload:  `{"ref":"refs/heads/releases/v1"}`,
+			expected: true,
+		},
+		{
+			desc:    "not matching branch",
+			filters: []FilterOption{Branches("main")},
+			payload: `{"ref":"refs/heads/feat"}`,
+		},
+		{
+			desc:    "ignored branch",
+			filters: []FilterOption{BranchesIgnore("dependabot/**")},
+			payload: `{"ref":"refs/heads/dependabot/go_modules/foo"}`,
+		},
+		{
+			desc:    "tag with only branch filters",
+			filters: []FilterOption{Branches("main")},
+			payload: `{"ref":"refs/tags/v1.0.0"}`,
+		},
+		{
+			desc:     "matching tag",
+			filters:  []FilterOption{Branches("main"), Tags("v*", "!v*-rc*")},
+			payload:  `{"ref":"refs/tags/v1.0.0"}`,
+			expected: true,
+		},
+		{
+			desc:    "negated tag",
+			filters: []FilterOption{Branches("main"), Tags("v*", "!v*-rc*")},
+			payload: `{"ref":"refs/tags/v1.0.0-rc1"}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			var called bool
+
+			action := NewAction(ctx).
+				OnPush(func(_ *github.Client, _ *github.PushEvent) error {
+					called = true
+					return nil
+				}, test.filters...)
+
+			err := action.Handle(ctx, "push", []byte(test.payload))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if called != test.expected {
+				t.Errorf("got called=%v, want %v", called, test.expected)
+			}
+		})
+	}
+}
+
+func TestAction_filters_pullRequest(t *testing.T) {
+	ctx := context.Background()
+
+	var called bool
+
+	action := NewAction(ctx).
+		OnPullRequest(func(_ *github.Client, _ *github.PullRequestEvent) error {
+			called = true
+			return nil
+		}, Branches("main"))
+
+	err := action.Handle(ctx, "pull_request", []byte(`{"number":1,"pull_request":{"base":{"ref":"develop"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if called {
+		t.Error("the handler must be skipped: the base branch does not match")
+	}
+}
@@ -0,0 +1,328 @@
+package ghactions
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v71/github"
+)
+
+// ErrActorRefused The actor of the event is refused by the guard.
+var ErrActorRefused = errors.New("actor refused by the guard")
+
+// Guard cache defaults.
+const (
+	// DefaultGuardCacheTTL the default duration of the results of the API calls of the guard.
+	DefaultGuardCacheTTL = 5 * time.Minute
+	// DefaultGuardCacheSize the default number of results kept by the guard.
+	DefaultGuardCacheSize = 1000
+)
+
+// GuardOption A rule of the guard.
+type GuardOption func(*guard)
+
+// RequirePermission Requires a minimum repository permission of the actor (read, triage, write, maintain, admin).
+func RequirePermission(level string) GuardOption {
+	return func(g *guard) {
+		g.permission = level
+	}
+}
+
+// DenyForks Refuses the pull requests from forks.
+func DenyForks() GuardOption {
+	return func(g *guard) {
+		g.denyForks = true
+	}
+}
+
+// AllowBots Allows the bots of the list (e.g. dependabot[bot]) without the other checks, the other bots are refused.
+func AllowBots(logins ...string) GuardOption {
+	return func(g *guard) {
+		g.bots = append(g.bots, logins...)
+	}
+}
+
+// RequireOrgMembership Requires the actor to be a member of an organization.
+func RequireOrgMembership(org string) GuardOption {
+	return func(g *guard) {
+		g.org = org
+	}
+}
+
+// WithGuardCache Defines how long (ttl) and how many (size) results of the API calls (permissions, memberships, forks) are kept.
+// The cache is disabled when ttl is 0, DefaultGuardCacheSize is used when size is lower than 1.
+func WithGuardCache(ttl time.Duration, size int) GuardOption {
+	return func(g *guard) {
+		g.cacheTTL = ttl
+		g.cacheSize = size
+	}
+}
+
+// Guard Defines the rules checked on the actor of the events before any handler is dispatched
+// (e.g. the sender of an issue_comment, or the actor of a workflow_run).
+// When a rule fails, an error annotation is created and the handler is not called: Handle returns ErrActorRefused.
+//
+//	action.Guard(ghactions.RequirePermission(ghactions.PermissionWrite), ghactions.DenyForks(), ghactions.AllowBots("dependabot[bot]"))
+func (a *Action) Guard(opts ...GuardOption) *Action {
+	g := &guard{cacheTTL: DefaultGuardCacheTTL, cacheSize: DefaultGuardCacheSize}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	g.cache = newGuardCache(g.cacheTTL, g.cacheSize)
+
+	a.guard = g
+
+	return a
+}
+
+type guard struct {
+	permission string
+	denyForks  bool
+	bots       []string
+	org        string
+
+	cacheTTL  time.Duration
+	cacheSize int
+	cache     *guardCache
+}
+
+type guardResult struct {
+	value string
+	ok    bool
+}
+
+// check checks the rules, the result is the reason of the refusal, or an empty string.
+func (g *guard) check(ctx context.Context, client *github.Client, event any) (string, error) {
+	actor, isBot := eventActor(event)
+	if actor == "" {
+		return "unable to find the actor of the event", nil
+	}
+
+	if isBot && len(g.bots) > 0 {
+		if slices.ContainsFunc(g.bots, func(login string) bool { return strings.EqualFold(login, actor) }) {
+			return "", nil
+		}
+
+		return fmt.Sprintf("the bot %s is not allowed", actor), nil
+	}
+
+	repo, err := RepoFromEvent(event)
+	if err != nil {
+		return "", err
+	}
+
+	if g.denyForks {
+		fork, err := g.isFork(ctx, client, repo, event)
+		if err != nil {
+			return "", err
+		}
+
+		if fork {
+			return "the pull request comes from a fork", nil
+		}
+	}
+
+	if g.permission != "" {
+		permission, err := g.cache.get(ctx, "permission:"+repo.FullName()+":"+actor, func() (string, bool, error) {
+			level, err := permissionLevel(ctx, client, repo, actor)
+			return level, true, err
+		})
+		if err != nil {
+			return "", err
+		}
+
+		if !hasPermission(permission.value, g.permission) {
+			return fmt.Sprintf("%s has the permission %q on %s, %q is required", actor, permission.value, repo.FullName(), g.permission), nil
+		}
+	}
+
+	if g.org != "" {
+		member, err := g.cache.get(ctx, "member:"+g.org+":"+actor, func() (string, bool, error) {
+			ok, _, err := client.Organizations.IsMember(ctx, g.org, actor)
+			if err != nil {
+				return "", false, fmt.Errorf("check the membership of %s in %s: %w", actor, g.org, err)
+			}
+
+			return "", ok, nil
+		})
+		if err != nil {
+			return "", err
+		}
+
+		if !member.ok {
+			return fmt.Sprintf("%s is not a member of the organization %s", actor, g.org), nil
+		}
+	}
+
+	return "", nil
+}
+
+// isFork checks if the pull request of the event comes from a fork.
+func (g *guard) isFork(ctx context.Context, client *github.Client, repo Repo, event any) (bool, error) {
+	var head, base string
+
+	switch evt := event.(type) {
+	case *github.PullRequestEvent:
+		head, base = evt.GetPullRequest().GetHead().GetRepo().GetFullName(), evt.GetPullRequest().GetBase().GetRepo().GetFullName()
+	case *github.PullRequestTargetEvent:
+		head, base = evt.GetPullRequest().GetHead().GetRepo().GetFullName(), evt.GetPullRequest().GetBase().GetRepo().GetFullName()
+	case *github.PullRequestReviewEvent:
+		head, base = evt.GetPullRequest().GetHead().GetRepo().GetFullName(), evt.GetPullRequest().GetBase().GetRepo().GetFullName()
+	case *github.PullRequestReviewCommentEvent:
+		head, base = evt.GetPullRequest().GetHead().GetRepo().GetFullName(), evt.GetPullRequest().GetBase().GetRepo().GetFullName()
+	case *github.WorkflowRunEvent:
+		head, base = evt.GetWorkflowRun().GetHeadRepository().GetFullName(), evt.GetWorkflowRun().GetRepository().GetFullName()
+	case *github.IssueCommentEvent:
+		if !evt.GetIssue().IsPullRequest() {
+			return false, nil
+		}
+
+		// the payload does not contain the head of the pull request.
+		result, err := g.cache.get(ctx, "fork:"+repo.FullName()+":"+fmt.Sprint(evt.GetIssue().GetNumber()), func() (string, bool, error) {
+			pr, _, err := client.PullRequests.Get(ctx, repo.Owner, repo.Name, evt.GetIssue().GetNumber())
+			if err != nil {
+				return "", false, fmt.Errorf("get the pull request #%d: %w", evt.GetIssue().GetNumber(), err)
+			}
+
+			return "", pr.GetHead().GetRepo().GetFullName() != pr.GetBase().GetRepo().GetFullName(), nil
+		})
+
+		return result.ok, err
+	default:
+		return false, nil
+	}
+
+	return head != "" && !strings.EqualFold(head, base), nil
+}
+
+// eventActor finds the actor of an event: the triggering actor of a workflow_run, the sender, or GITHUB_ACTOR.
+func eventActor(event any) (string, bool) {
+	if evt, ok := event.(*github.WorkflowRunEvent); ok {
+		for _, user := range []*github.User{evt.GetWorkflowRun().GetTriggeringActor(), evt.GetWorkflowRun().GetActor()} {
+			if user.GetLogin() != "" {
+				return user.GetLogin(), isBot(user)
+			}
+		}
+	}
+
+	if evt, ok := event.(interface{ GetSender() *github.User }); ok && evt.GetSender().GetLogin() != "" {
+		return evt.GetSender().GetLogin(), isBot(evt.GetSender())
+	}
+
+	actor := os.Getenv(GithubActor)
+
+	return actor, strings.HasSuffix(actor, "[bot]")
+}
+
+func isBot(user *github.User) bool {
+	return user.GetType() == "Bot" || strings.HasSuffix(user.GetLogin(), "[bot]")
+}
+
+// guardCache A bounded cache of the results of the guard, with a TTL: the oldest results are evicted first.
+// The concurrent lookups of the same key share the same API call, the API is called outside the lock.
+type guardCache struct {
+	ttl  time.Duration
+	size int
+	now  func() time.Time
+
+	mu       sync.Mutex
+	order    *list.List // *guardEntry, the oldest first.
+	entries  map[string]*list.Element
+	inflight map[string]*guardCall
+}
+
+type guardEntry struct {
+	key     string
+	result  guardResult
+	expires time.Time
+}
+
+type guardCall struct {
+	done   chan struct{}
+	result guardResult
+	err    error
+}
+
+func newGuardCache(ttl time.Duration, size int) *guardCache {
+	if size < 1 {
+		size = DefaultGuardCacheSize
+	}
+
+	return &guardCache{
+		ttl:      ttl,
+		size:     size,
+		now:      time.Now,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+		inflight: make(map[string]*guardCall),
+	}
+}
+
+// get returns the result of a key, fn is called when the result is missing or expired.
+func (c *guardCache) get(ctx context.Context, key string, fn func() (string, bool, error)) (guardResult, error) {
+	c.mu.Lock()
+
+	if elt, ok := c.entries[key]; ok {
+		entry := elt.Value.(*guardEntry)
+		if c.now().Before(entry.expires) {
+			c.mu.Unlock()
+			return entry.result, nil
+		}
+
+		c.order.Remove(elt)
+		delete(c.entries, key)
+	}
+
+	if call, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+
+		select {
+		case <-call.done:
+			return call.result, call.err
+		case <-ctx.Done():
+			return guardResult{}, ctx.Err()
+		}
+	}
+
+	// the error of the waiters when fn panics.
+	call := &guardCall{done: make(chan struct{}), err: fmt.Errorf("guard: the lookup of %s has been interrupted", key)}
+	c.inflight[key] = call
+
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.inflight, key)
+
+		if call.err == nil && c.ttl > 0 {
+			c.add(key, call.result)
+		}
+
+		c.mu.Unlock()
+
+		close(call.done)
+	}()
+
+	value, ok, err := fn()
+	call.result, call.err = guardResult{value: value, ok: ok}, err
+
+	return call.result, call.err
+}
+
+func (c *guardCache) add(key string, result guardResult) {
+	c.entries[key] = c.order.PushBack(&guardEntry{key: key, result: result, expires: c.now().Add(c.ttl)})
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Front()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*guardEntry).key)
+	}
+}
@@ -0,0 +1,276 @@
+package ghactions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v71/github"
+)
+
+func TestAction_Guard(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		opts      []GuardOption
+		eventName string
+		payload   string
+		refused   bool
+	}{
+		{
+			desc:      "permission granted",
+			opts:      []GuardOption{RequirePermission(PermissionWrite)},
+			eventName: "issue_comment",
+			payload:   `{"sender":{"login":"maintainer"},"issue":{"number":1},"repository":{"full_name":"ldez/ghactions"}}`,
+		},
+		{
+			desc:      "permission refused",
+			opts:      []GuardOption{RequirePermission(PermissionWrite)},
+			eventName: "issue_comment",
+			payload:   `{"sender":{"login":"stranger"},"issue":{"number":1},"repository":{"full_name":"ldez/ghactions"}}`,
+			refused:   true,
+		},
+		{
+			desc:      "fork refused",
+			opts:      []GuardOption{DenyForks()},
+			eventName: "pull_request_target",
+			payload: `{"sender":{"login":"maintainer"},"repository":{"full_name":"ldez/ghactions"},` +
+				`"pull_request":{"head":{"repo":{"full_name":"stranger/ghactions"}},"base":{"repo":{"full_name":"ldez/ghactions"}}}}`,
+			refused: true,
+		},
+		{
+			desc:      "fork refused on a pull request comment",
+			opts:      []GuardOption{DenyForks()},
+			eventName: "issue_comment",
+			payload: `{"sender":{"login":"maintainer"},"repository":{"full_name":"ldez/ghactions"},` +
+				`"issue":{"number":5,"pull_request":{"url":"https://api.github.com/repos/ldez/ghactions/pulls/5"}}}`,
+			refused: true,
+		},
+		{
+			desc:      "same repository",
+			opts:      []GuardOption{DenyForks()},
+			eventName: "pull_request_target",
+			payload: `{"sender":{"login":"maintainer"},"repository":{"full_name":"ldez/ghactions"},` +
+				`"pull_request":{"head":{"repo":{"full_name":"ldez/ghactions"}},"base":{"repo":{"full_name":"ldez/ghactions"}}}}`,
+		},
+		{
+			desc:      "allowed bot",
+			opts:      []GuardOption{RequirePermission(PermissionWrite), AllowBots("dependabot[bot]")},
+			eventName: "pull_request_target",
+			payload:   `{"sender":{"login":"dependabot[bot]","type":"Bot"},"repository":{"full_name":"ldez/ghactions"}}`,
+		},
+		{
+			desc:      "other bot",
+			opts:      []GuardOption{AllowBots("dependabot[bot]")},
+			eventName: "pull_request_target",
+			payload:   `{"sender":{"login":"renovate[bot]","type":"Bot"},"repository":{"full_name":"ldez/ghactions"}}`,
+			refused:   true,
+		},
+		{
+			desc:      "workflow_run triggering actor",
+			opts:      []GuardOption{RequirePermission(PermissionWrite)},
+			eventName: "workflow_run",
+			payload: `{"sender":{"login":"maintainer"},"repository":{"full_name":"ldez/ghactions"},` +
+				`"workflow_run":{"triggering_actor":{"login":"stranger"}}}`,
+			refused: true,
+		},
+		{
+			desc:      "organization member",
+			opts:      []GuardOption{RequireOrgMembership("traefik")},
+			eventName: "issue_comment",
+			payload:   `{"sender":{"login":"maintainer"},"issue":{"number":1},"repository":{"full_name":"ldez/ghactions"}}`,
+		},
+		{
+			desc:      "not an organization member",
+			opts:      []GuardOption{RequireOrgMembership("traefik")},
+			eventName: "issue_comment",
+			payload:   `{"sender":{"login":"stranger"},"issue":{"number":1},"repository":{"full_name":"ldez/ghactions"}}`,
+			refused:   true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			buf := captureCommands(t)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v3/repos/ldez/ghactions/collaborators/{user}/permission", func(rw http.ResponseWriter, req *http.Request) {
+				permission := "read"
+				if req.PathValue("user") == "maintainer" {
+					permission = "write"
+				}
+
+				_, _ = fmt.Fprintf(rw, `{"permission":%q}`, permission)
+			})
+			mux.HandleFunc("GET /api/v3/repos/ldez/ghactions/pulls/5", func(rw http.ResponseWriter, _ *http.Request) {
+				_, _ = rw.Write([]byte(`{"head":{"repo":{"full_name":"stranger/ghactions"}},"base":{"repo":{"full_name":"ldez/ghactions"}}}`))
+			})
+			mux.HandleFunc("GET /api/v3/orgs/traefik/members/{user}", func(rw http.ResponseWriter, req *http.Request) {
+				if req.PathValue("user") == "maintainer" {
+					rw.WriteHeader(http.StatusNoContent)
+					return
+				}
+
+				rw.WriteHeader(http.StatusNotFound)
+			})
+
+			var called bool
+
+			handler := func(*github.Client) { called = true }
+
+			action := newTestAction(t, mux).
+				Guard(test.opts...).
+				OnIssueComment(func(client *github.Client, _ *github.IssueCommentEvent) error { handler(client); return nil }).
+				OnPullRequestTarget(func(client *github.Client, _ *github.PullRequestTargetEvent) error { handler(client); return nil }).
+				OnWorkflowRun(func(client *github.Client, _ *github.WorkflowRunEvent) error { handler(client); return nil })
+
+			err := action.Handle(context.Background(), test.eventName, []byte(test.payload))
+
+			if test.refused {
+				if !errors.Is(err, ErrActorRefused) {
+					t.Fatalf("got %v, want %v", err, ErrActorRefused)
+				}
+
+				if called {
+					t.Error("the handler must not be called")
+				}
+
+				if !strings.HasPrefix(buf.String(), "::error title=Event refused::") {
+					t.Errorf("missing annotation: %q", buf.String())
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !called {
+				t.Error("the handler must be called")
+			}
+		})
+	}
+}
+
+func TestAction_Guard_cache(t *testing.T) {
+	var calls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/repos/ldez/ghactions/collaborators/maintainer/permission", func(rw http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		_, _ = rw.Write([]byte(`{"permission":"admin"}`))
+	})
+
+	action := newTestAction(t, mux).
+		Guard(RequirePermission(PermissionWrite)).
+		OnIssueComment(func(*github.Client, *github.IssueCommentEvent) error { return nil })
+
+	payload := []byte(`{"sender":{"login":"maintainer"},"issue":{"number":1},"repository":{"full_name":"ldez/ghactions"}}`)
+
+	for range 3 {
+		err := action.Handle(context.Background(), "issue_comment", payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("got %d API calls, want 1", calls.Load())
+	}
+}
+
+func Test_guardCache(t *testing.T) {
+	now := time.Now()
+
+	cache := newGuardCache(time.Minute, 2)
+	cache.now = func() time.Time { return now }
+
+	var calls int
+
+	get := func(key string) {
+		t.Helper()
+
+		_, err := cache.get(context.Background(), key, func() (string, bool, error) {
+			calls++
+			return key, true, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	get("a")
+	get("a")
+
+	if calls != 1 {
+		t.Errorf("got %d calls, want 1", calls)
+	}
+
+	// expired.
+	now = now.Add(2 * time.Minute)
+	get("a")
+
+	if calls != 2 {
+		t.Errorf("expired: got %d calls, want 2", calls)
+	}
+
+	// "a" is evicted (the oldest).
+	get("b")
+	get("c")
+	get("a")
+
+	if calls != 5 {
+		t.Errorf("evicted: got %d calls, want 5", calls)
+	}
+
+	if cache.order.Len() != 2 {
+		t.Errorf("got %d entries, want 2", cache.order.Len())
+	}
+}
+
+func Test_guardCache_concurrent(t *testing.T) {
+	cache := newGuardCache(time.Minute, 10)
+
+	var calls atomic.Int32
+
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+
+	for range 5 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			result, err := cache.get(context.Background(), "slow", func() (string, bool, error) {
+				calls.Add(1)
+				<-release
+
+				return "admin", true, nil
+			})
+			if err != nil || result.value != "admin" {
+				t.Errorf("got %v, %v", result, err)
+			}
+		}()
+	}
+
+	// the lock is not held during a lookup: the other keys are not blocked.
+	_, err := cache.get(context.Background(), "other", func() (string, bool, error) { return "", true, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("got %d calls, want 1", calls.Load())
+	}
+}
@@ -0,0 +1,397 @@
+package ghactions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v71/github"
+	"gopkg.in/yaml.v3"
+)
+
+// InputsOption An option of OnWorkflowDispatchInputs.
+type InputsOption func(*inputsConfig)
+
+type inputsConfig struct {
+	validate     bool
+	workflowPath string
+}
+
+// WithInputsValidation Validates the inputs against the `workflow_dispatch.inputs` declared in the workflow file.
+// When workflowPath is empty, the workflow file is fetched from the API, at GITHUB_WORKFLOW_REF and GITHUB_WORKFLOW_SHA
+// (the workspace can contain another version of the file).
+func WithInputsValidation(workflowPath string) InputsOption {
+	return func(cfg *inputsConfig) {
+		cfg.validate = true
+		cfg.workflowPath = workflowPath
+	}
+}
+
+// OnWorkflowDispatchInputs Workflow Dispatch handler with typed inputs.
+// The inputs are decoded into T (a struct, using the json tags):
+// the string values sent by the dispatch forms are converted to the types of the fields (bool, numbers).
+// It replaces the handler defined by OnWorkflowDispatch.
+func OnWorkflowDispatchInputs[T any](a *Action, eventHandler func(*github.Client, *github.WorkflowDispatchEvent, T) error, opts ...InputsOption) *Action {
+	cfg := &inputsConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return a.OnWorkflowDispatch(func(client *github.Client, event *github.WorkflowDispatchEvent) error {
+		if cfg.validate {
+			err := validateDispatchInputs(a.ctx, client, cfg.workflowPath, event.Inputs)
+			if err != nil {
+				return err
+			}
+		}
+
+		var inputs T
+
+		err := DecodeInputs(event.Inputs, &inputs)
+		if err != nil {
+			return err
+		}
+
+		return eventHandler(client, event, inputs)
+	})
+}
+
+// DecodeInputs Decodes the inputs of a workflow_dispatch event into v.
+// The string values are converted to the types of the struct fields (bool, numbers).
+func DecodeInputs(raw json.RawMessage, v any) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return errors.New("decode inputs: a non-nil pointer is required")
+	}
+
+	target := rv.Elem()
+	if target.Kind() != reflect.Struct {
+		return json.Unmarshal(raw, v)
+	}
+
+	values := map[string]json.RawMessage{}
+
+	err := json.Unmarshal(raw, &values)
+	if err != nil {
+		return fmt.Errorf("decode inputs: %w", err)
+	}
+
+	for i := range target.NumField() {
+		field := target.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, ok := inputName(field)
+		if !ok {
+			continue
+		}
+
+		value, ok := lookupInput(values, name)
+		if !ok {
+			continue
+		}
+
+		err = decodeInput(value, target.Field(i))
+		if err != nil {
+			return fmt.Errorf("decode input %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// DecodeActionInputs Decodes the inputs of the action (INPUT_<NAME>) into v (a struct, using the json tags).
+// The string values are converted to the types of the struct fields (bool, numbers).
+//
+// Inside a reusable workflow (workflow_call), the event is the event of the caller:
+// the inputs of the reusable workflow are given to the action as action inputs (`with: dry-run: ${{ inputs.dry-run }}`).
+func DecodeActionInputs(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("decode action inputs: a non-nil pointer to a struct is required")
+	}
+
+	values := map[string]string{}
+
+	target := rv.Elem().Type()
+
+	for i := range target.NumField() {
+		field := target.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, ok := inputName(field)
+		if !ok {
+			continue
+		}
+
+		if value, ok := os.LookupEnv(inputEnvName(name)); ok {
+			values[name] = value
+		}
+	}
+
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	return DecodeInputs(raw, v)
+}
+
+func inputName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+
+	return name, true
+}
+
+// lookupInput finds an input by name, the exact match is preferred (like encoding/json).
+func lookupInput(values map[string]json.RawMessage, name string) (json.RawMessage, bool) {
+	if value, ok := values[name]; ok {
+		return value, true
+	}
+
+	for key, value := range values {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+
+	return nil, false
+}
+
+func decodeInput(raw json.RawMessage, field reflect.Value) error {
+	var s string
+	if json.Unmarshal(raw, &s) != nil {
+		// not a string: standard decoding.
+		return json.Unmarshal(raw, field.Addr().Interface())
+	}
+
+	if field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+
+		field = field.Elem()
+	}
+
+	s = strings.TrimSpace(s)
+
+	switch field.Kind() {
+	case reflect.Bool:
+		if s == "" {
+			return nil
+		}
+
+		b, err := parseBoolInput(s)
+		if err != nil {
+			return err
+		}
+
+		field.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if s == "" {
+			return nil
+		}
+
+		n, err := strconv.ParseInt(s, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if s == "" {
+			return nil
+		}
+
+		n, err := strconv.ParseUint(s, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		if s == "" {
+			return nil
+		}
+
+		f, err := strconv.ParseFloat(s, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetFloat(f)
+
+	default:
+		return json.Unmarshal(raw, field.Addr().Interface())
+	}
+
+	return nil
+}
+
+// parseBoolInput parses a boolean input: only true and false are valid (like the boolean inputs of GitHub).
+func parseBoolInput(s string) (bool, error) {
+	switch s {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid boolean %q: must be true or false", s)
+	}
+}
+
+// dispatchInput an input declared in `on.workflow_dispatch.inputs`.
+type dispatchInput struct {
+	Description string   `yaml:"description"`
+	Required    bool     `yaml:"required"`
+	Default     any      `yaml:"default"`
+	Type        string   `yaml:"type"`
+	Options     []string `yaml:"options"`
+}
+
+func validateDispatchInputs(ctx context.Context, client *github.Client, workflowPath string, raw json.RawMessage) error {
+	var (
+		content []byte
+		err     error
+	)
+
+	if workflowPath == "" {
+		workflowPath, content, err = fetchWorkflowFile(ctx, client)
+	} else {
+		content, err = os.ReadFile(filepath.Clean(workflowPath))
+	}
+
+	if err != nil {
+		return fmt.Errorf("read workflow file: %w", err)
+	}
+
+	declared, err := parseDispatchInputs(workflowPath, content)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]any{}
+	if len(raw) > 0 {
+		err = json.Unmarshal(raw, &values)
+		if err != nil {
+			return fmt.Errorf("invalid inputs: %w", err)
+		}
+	}
+
+	var errs []error
+
+	for name := range values {
+		if _, ok := declared[name]; !ok {
+			errs = append(errs, fmt.Errorf("input %q is not declared in %s", name, workflowPath))
+		}
+	}
+
+	names := make([]string, 0, len(declared))
+	for name := range declared {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		err = declared[name].validate(name, values[name])
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (d dispatchInput) validate(name string, value any) error {
+	s := strings.TrimSpace(fmt.Sprint(value))
+	if value == nil {
+		s = ""
+	}
+
+	if s == "" {
+		if d.Required && d.Default == nil {
+			return fmt.Errorf("input %q is required", name)
+		}
+
+		return nil
+	}
+
+	switch d.Type {
+	case "boolean":
+		if _, err := parseBoolInput(s); err != nil {
+			return fmt.Errorf("input %q: %w", name, err)
+		}
+
+	case "number":
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return fmt.Errorf("input %q: invalid number %q", name, s)
+		}
+
+	case "choice":
+		if !slices.Contains(d.Options, s) {
+			return fmt.Errorf("input %q: %q is not one of %v", name, s, d.Options)
+		}
+	}
+
+	return nil
+}
+
+// parseDispatchInputs reads the inputs declared in `on.workflow_dispatch.inputs`.
+func parseDispatchInputs(workflowPath string, content []byte) (map[string]dispatchInput, error) {
+	var workflow struct {
+		On yaml.Node `yaml:"on"`
+	}
+
+	err := yaml.Unmarshal(content, &workflow)
+	if err != nil {
+		return nil, fmt.Errorf("parse workflow file %s: %w", workflowPath, err)
+	}
+
+	if workflow.On.Kind != yaml.MappingNode {
+		// `on: workflow_dispatch` or `on: [push, workflow_dispatch]`: no inputs.
+		return map[string]dispatchInput{}, nil
+	}
+
+	var on struct {
+		WorkflowDispatch struct {
+			Inputs map[string]dispatchInput `yaml:"inputs"`
+		} `yaml:"workflow_dispatch"`
+	}
+
+	err = workflow.On.Decode(&on)
+	if err != nil {
+		return nil, fmt.Errorf("parse workflow file %s: %w", workflowPath, err)
+	}
+
+	if on.WorkflowDispatch.Inputs == nil {
+		return map[string]dispatchInput{}, nil
+	}
+
+	return on.WorkflowDispatch.Inputs, nil
+}
@@ -0,0 +1,143 @@
+package ghactions
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/google/go-github/v71/github"
+)
+
+type deployInputs struct {
+	Environment string  `json:"environment"`
+	DryRun      bool    `json:"dry-run"`
+	Replicas    int     `json:"replicas"`
+	Ratio       float64 `json:"ratio"`
+	Label       *string `json:"label"`
+}
+
+func TestDecodeInputs(t *testing.T) {
+	var inputs deployInputs
+
+	err := DecodeInputs([]byte(`{"environment":"staging","dry-run":"false","replicas":"3","ratio":"0.5","label":"bug"}`), &inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if inputs.Environment != "staging" || inputs.DryRun || inputs.Replicas != 3 || inputs.Ratio != 0.5 {
+		t.Errorf("unexpected inputs: %+v", inputs)
+	}
+
+	if inputs.Label == nil || *inputs.Label != "bug" {
+		t.Errorf("unexpected label: %v", inputs.Label)
+	}
+
+	for _, payload := range []string{`{"replicas":"three"}`, `{"dry-run":"1"}`, `{"dry-run":"TRUE"}`} {
+		err = DecodeInputs([]byte(payload), &inputs)
+		if err == nil {
+			t.Errorf("%s: expected an error", payload)
+		}
+	}
+}
+
+func TestOnWorkflowDispatchInputs(t *testing.T) {
+	workflow, err := os.ReadFile("./fixtures/workspace/.github/workflows/dispatch.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the workspace does not contain the workflow file: it is fetched from the API at GITHUB_WORKFLOW_SHA.
+	t.Setenv(GithubWorkspace, t.TempDir())
+	t.Setenv(GithubWorkflowRef, "ldez/ghactions/.github/workflows/dispatch.yml@refs/heads/master")
+	t.Setenv(GithubWorkflowSha, "abc")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/repos/ldez/ghactions/contents/.github/workflows/dispatch.yml", func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("ref") != "abc" {
+			http.Error(rw, `{"message":"Not Found"}`, http.StatusNotFound)
+			return
+		}
+
+		_ = json.NewEncoder(rw).Encode(map[string]string{
+			"type":     "file",
+			"encoding": "base64",
+			"content":  base64.StdEncoding.EncodeToString(workflow),
+		})
+	})
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		desc    string
+		payload string
+		path    string
+		wantErr bool
+	}{
+		{
+			desc:    "valid",
+			payload: `{"inputs":{"environment":"production","dry-run":"true","replicas":"2"}}`,
+		},
+		{
+			desc:    "local workflow file",
+			payload: `{"inputs":{"environment":"production","dry-run":"true","replicas":"2"}}`,
+			path:    "./fixtures/workspace/.github/workflows/dispatch.yml",
+		},
+		{
+			desc:    "invalid boolean",
+			payload: `{"inputs":{"environment":"staging","dry-run":"1"}}`,
+			wantErr: true,
+		},
+		{
+			desc:    "missing required",
+			payload: `{"inputs":{"dry-run":"true"}}`,
+			wantErr: true,
+		},
+		{
+			desc:    "invalid choice",
+			payload: `{"inputs":{"environment":"dev"}}`,
+			wantErr: true,
+		},
+		{
+			desc:    "invalid number",
+			payload: `{"inputs":{"environment":"staging","replicas":"two"}}`,
+			wantErr: true,
+		},
+		{
+			desc:    "undeclared input",
+			payload: `{"inputs":{"environment":"staging","force":"true"}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			var got deployInputs
+
+			action := newTestAction(t, mux)
+
+			OnWorkflowDispatchInputs(action, func(_ *github.Client, _ *github.WorkflowDispatchEvent, inputs deployInputs) error {
+				got = inputs
+				return nil
+			}, WithInputsValidation(test.path))
+
+			err := action.Handle(ctx, "workflow_dispatch", []byte(test.payload))
+			if test.wantErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got.Environment != "production" || !got.DryRun || got.Replicas != 2 {
+				t.Errorf("unexpected inputs: %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+// Package events The events shared by the generator and the CLI.
+package events
+
+// Local the events handled by ghactions with its own types: event name -> type.
+var Local = map[string]string{
+	"project":             "ClassicProjectEvent",
+	"project_card":        "ClassicProjectCardEvent",
+	"project_column":      "ClassicProjectColumnEvent",
+	"registry_package":    "RegistryPackageEvent",
+	"repository_advisory": "RepositoryAdvisoryEvent",
+	"schedule":            "ScheduleEvent",
+}
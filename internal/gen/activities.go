@@ -0,0 +1,93 @@
+package main
+
+// activityTypes the valid actions (activity types) of the events.
+// The events without activity types are not listed.
+// https://docs.github.com/en/webhooks/webhook-events-and-payloads
+// https://docs.github.com/en/actions/writing-workflows/choosing-when-your-workflow-runs/events-that-trigger-workflows
+var activityTypes = map[string][]string{
+	"branch_protection_configuration": {"disabled", "enabled"},
+	"branch_protection_rule":          {"created", "deleted", "edited"},
+	"check_run":                       {"completed", "created", "requested_action", "rerequested"},
+	"check_suite":                     {"completed", "requested", "rerequested"},
+	"code_scanning_alert":             {"appeared_in_branch", "closed_by_user", "created", "fixed", "reopened", "reopened_by_user"},
+	"commit_comment":                  {"created"},
+	"content_reference":               {"created"},
+	"custom_property":                 {"created", "deleted", "promote_to_enterprise", "updated"},
+	"custom_property_values":          {"updated"},
+	"dependabot_alert":                {"auto_dismissed", "auto_reopened", "created", "dismissed", "fixed", "reintroduced", "reopened"},
+	"deploy_key":                      {"created", "deleted"},
+	"deployment":                      {"created"},
+	"deployment_protection_rule":      {"requested"},
+	"deployment_review":               {"approved", "rejected", "requested"},
+	"deployment_status":               {"created"},
+	"discussion": {
+		"answered", "category_changed", "closed", "created", "deleted", "edited", "labeled", "locked",
+		"pinned", "reopened", "transferred", "unanswered", "unlabeled", "unlocked", "unpinned",
+	},
+	"discussion_comment":        {"created", "deleted", "edited"},
+	"github_app_authorization":  {"revoked"},
+	"installation":              {"created", "deleted", "new_permissions_accepted", "suspend", "unsuspend"},
+	"installation_repositories": {"added", "removed"},
+	"installation_target":       {"renamed"},
+	"issue_comment":             {"created", "deleted", "edited"},
+	"issues": {
+		"assigned", "closed", "deleted", "demilestoned", "edited", "labeled", "locked", "milestoned", "opened",
+		"pinned", "reopened", "transferred", "typed", "unassigned", "unlabeled", "unlocked", "unpinned", "untyped",
+	},
+	"label":                         {"created", "deleted", "edited"},
+	"marketplace_purchase":          {"cancelled", "changed", "pending_change", "pending_change_cancelled", "purchased"},
+	"member":                        {"added", "edited", "removed"},
+	"membership":                    {"added", "removed"},
+	"merge_group":                   {"checks_requested", "destroyed"},
+	"meta":                          {"deleted"},
+	"milestone":                     {"closed", "created", "deleted", "edited", "opened"},
+	"org_block":                     {"blocked", "unblocked"},
+	"organization":                  {"deleted", "member_added", "member_invited", "member_removed", "renamed"},
+	"package":                       {"published", "updated"},
+	"personal_access_token_request": {"approved", "cancelled", "created", "denied"},
+	"project":                       {"closed", "created", "deleted", "edited", "reopened"},
+	"project_card":                  {"converted", "created", "deleted", "edited", "moved"},
+	"project_column":                {"created", "deleted", "edited", "moved"},
+	"projects_v2":                   {"closed", "created", "deleted", "edited", "reopened"},
+	"projects_v2_item":              {"archived", "converted", "created", "deleted", "edited", "reordered", "restored"},
+	"pull_request":                  pullRequestActivityTypes,
+	"pull_request_review":           {"dismissed", "edited", "submitted"},
+	"pull_request_review_comment":   {"created", "deleted", "edited"},
+	"pull_request_review_thread":    {"resolved", "unresolved"},
+	"pull_request_target":           pullRequestActivityTypes,
+	"registry_package":              {"published", "updated"},
+	"release":                       {"created", "deleted", "edited", "prereleased", "published", "released", "unpublished"},
+	"repository": {
+		"archived", "created", "deleted", "edited", "privatized", "publicized", "renamed", "transferred", "unarchived",
+	},
+	"repository_advisory":            {"published", "reported"},
+	"repository_ruleset":             {"created", "deleted", "edited"},
+	"repository_vulnerability_alert": {"create", "dismiss", "reopen", "resolve"},
+	"secret_scanning_alert":          {"created", "publicly_leaked", "reopened", "resolved", "validated"},
+	"secret_scanning_alert_location": {"created"},
+	"security_advisory":              {"published", "updated", "withdrawn"},
+	"sponsorship":                    {"cancelled", "created", "edited", "pending_cancellation", "pending_tier_change", "tier_changed"},
+	"star":                           {"created", "deleted"},
+	"team":                           {"added_to_repository", "created", "deleted", "edited", "removed_from_repository"},
+	"user":                           {"created", "deleted"},
+	"watch":                          {"started"},
+	"workflow_job":                   {"completed", "in_progress", "queued", "waiting"},
+	"workflow_run":                   {"completed", "in_progress", "requested"},
+}
+
+var pullRequestActivityTypes = []string{
+	"assigned", "auto_merge_disabled", "auto_merge_enabled", "closed", "converted_to_draft", "demilestoned",
+	"dequeued", "edited", "enqueued", "labeled", "locked", "milestoned", "opened", "ready_for_review", "reopened",
+	"review_request_removed", "review_requested", "synchronize", "unassigned", "unlabeled", "unlocked",
+}
+
+// extraEvents the webhook events and the workflow triggers that are not parsed by github.ParseWebHook.
+var extraEvents = []string{
+	"project",
+	"project_card",
+	"project_column",
+	"registry_package",
+	"repository_advisory",
+	"schedule",
+	"workflow_call",
+}
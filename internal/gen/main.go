@@ -0,0 +1,186 @@
+// Generates the event handlers of ghactions.Action and the event name constants from the events supported by go-github.
+//
+// Usage (from the root of the module):
+//
+//	go run ./internal/gen
+package main
+
+import (
+	"bytes"
+	"embed"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/ldez/ghactions/internal/events"
+)
+
+//go:embed *.tmpl
+var templates embed.FS
+
+// names overrides the name of some handlers, to keep the existing API.
+// The names of the constants are always the names of the go-github types (ex: ProjectV2).
+var names = map[string]string{
+	"ProjectV2Event":     "Project",
+	"ProjectV2ItemEvent": "ProjectItem",
+}
+
+// filteredEvents the events that support the branch and tag filters.
+var filteredEvents = map[string]bool{
+	"create":              true,
+	"pull_request":        true,
+	"pull_request_target": true,
+	"push":                true,
+}
+
+// outputs generated file -> template.
+var outputs = map[string]string{
+	"actions_gen.go":     "actions.go.tmpl",
+	"event/event_gen.go": "event.go.tmpl",
+}
+
+// Data the data used by the templates.
+type Data struct {
+	Events   []Event // the events supported by go-github.
+	Extras   []Event // the events not supported by go-github.
+	Handlers []Event // the events with a handler: the events supported by go-github and the local events.
+	Locals   []Event // the events not supported by go-github, with a type of ghactions (see events.Local).
+}
+
+// Event an event.
+type Event struct {
+	Name     string   // webhook name (ex: pull_request).
+	Const    string   // constant name (ex: PullRequest).
+	Type     string   // go-github type (ex: PullRequestEvent), empty for the extra events.
+	GoType   string   // qualified type, from the ghactions package (ex: github.PullRequestEvent).
+	Handler  string   // handler name (ex: PullRequest), empty for the extra events.
+	Actions  []string // activity types.
+	Filtered bool     // supports the branch and tag filters.
+}
+
+func main() {
+	root := flag.String("root", ".", "Root directory of the module.")
+	flag.Parse()
+
+	files, err := generate(loadData())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for path, content := range files {
+		err = os.WriteFile(filepath.Join(*root, path), content, 0o600)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// loadData reads the events supported by go-github (github.ParseWebHook) and adds the extra events.
+func loadData() Data {
+	var data Data
+
+	for _, name := range github.MessageTypes() {
+		typeName := reflect.TypeOf(github.EventForType(name)).Elem().Name()
+
+		handler, ok := names[typeName]
+		if !ok {
+			handler = strings.TrimSuffix(typeName, "Event")
+		}
+
+		data.Events = append(data.Events, Event{
+			Name:     name,
+			Const:    strings.TrimSuffix(typeName, "Event"),
+			Type:     typeName,
+			GoType:   "github." + typeName,
+			Handler:  handler,
+			Actions:  activityTypes[name],
+			Filtered: filteredEvents[name],
+		})
+	}
+
+	for _, name := range extraEvents {
+		if github.EventForType(name) != nil {
+			// now supported by go-github.
+			continue
+		}
+
+		data.Extras = append(data.Extras, Event{
+			Name:    name,
+			Const:   toCamel(name),
+			Actions: activityTypes[name],
+		})
+	}
+
+	data.Handlers = slices.Clone(data.Events)
+
+	for name, typeName := range events.Local {
+		data.Locals = append(data.Locals, Event{
+			Name:    name,
+			Const:   toCamel(name),
+			Type:    typeName,
+			GoType:  typeName,
+			Handler: strings.TrimSuffix(typeName, "Event"),
+		})
+	}
+
+	data.Handlers = append(data.Handlers, data.Locals...)
+
+	byConst := func(a, b Event) int { return strings.Compare(a.Const, b.Const) }
+
+	slices.SortFunc(data.Events, byConst)
+	slices.SortFunc(data.Extras, byConst)
+	slices.SortFunc(data.Locals, byConst)
+	slices.SortFunc(data.Handlers, func(a, b Event) int { return strings.Compare(a.Handler, b.Handler) })
+
+	return data
+}
+
+// generate renders the generated files: path -> content.
+func generate(data Data) (map[string][]byte, error) {
+	tmpl, err := template.New("").Funcs(template.FuncMap{
+		"quote": strconv.Quote,
+	}).ParseFS(templates, "*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte)
+
+	for path, name := range outputs {
+		buf := &bytes.Buffer{}
+
+		err = tmpl.ExecuteTemplate(buf, name, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		files[path], err = format.Source(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	return files, nil
+}
+
+// toCamel converts an event name to a Go name (ex: project_card -> ProjectCard).
+func toCamel(name string) string {
+	var b strings.Builder
+
+	for _, part := range strings.Split(name, "_") {
+		if part != "" {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+
+	return b.String()
+}
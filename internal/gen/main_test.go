@@ -0,0 +1,26 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerate_upToDate(t *testing.T) {
+	files, err := generate(loadData())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, content := range files {
+		current, err := os.ReadFile(filepath.Join("..", "..", path))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(current, content) {
+			t.Errorf("%s is not up to date: run 'go generate ./...'", path)
+		}
+	}
+}
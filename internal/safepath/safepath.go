@@ -0,0 +1,78 @@
+// Package safepath The rules of the extraction of the archives (tool cache, caches):
+// the entries must stay inside the destination, and the links are never followed.
+package safepath
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Join joins the name of an archive entry to the destination, the entries outside the destination are refused.
+func Join(dest, name string) (string, error) {
+	target := filepath.Join(dest, name)
+
+	if !Within(dest, target) {
+		return "", fmt.Errorf("invalid path in the archive: %s", name)
+	}
+
+	return target, nil
+}
+
+// Within checks if a path is the directory dir or is inside it.
+func Within(dir, path string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(os.PathSeparator))
+}
+
+// Target returns the path of an archive entry inside the destination:
+// the entries outside the destination, and the entries through a link (or replacing a link) are refused.
+// The links are checked on disk: a link of the archive can point to another link (ex: a/l -> .., a/l/l2 -> .., a/l/l2/x).
+func Target(dest, name string) (string, error) {
+	target, err := Join(dest, name)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(dest, target)
+	if err != nil || rel == "." {
+		return target, err
+	}
+
+	current := dest
+
+	for _, part := range strings.Split(rel, string(os.PathSeparator)) {
+		current = filepath.Join(current, part)
+
+		info, err := os.Lstat(current)
+		if errors.Is(err, os.ErrNotExist) {
+			// the next parts do not exist either.
+			return target, nil
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			return "", fmt.Errorf("invalid path in the archive: %s (through the link %s)", name, current)
+		}
+	}
+
+	return target, nil
+}
+
+// Link checks a symbolic link of an archive entry: the absolute links and the links outside the destination are refused.
+func Link(dest, name, linkname string) error {
+	if filepath.IsAbs(linkname) {
+		return fmt.Errorf("invalid link in the archive: %s -> %s", name, linkname)
+	}
+
+	_, err := Join(dest, filepath.Join(filepath.Dir(name), linkname))
+	if err != nil {
+		return fmt.Errorf("invalid link in the archive: %s -> %s", name, linkname)
+	}
+
+	return nil
+}
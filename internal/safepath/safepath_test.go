@@ -0,0 +1,116 @@
+package safepath
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTarget(t *testing.T) {
+	dest := t.TempDir()
+
+	err := os.MkdirAll(filepath.Join(dest, "dir"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.Symlink("dir", filepath.Join(dest, "link"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		desc     string
+		name     string
+		expected string
+		invalid  bool
+	}{
+		{
+			desc:     "root",
+			name:     ".",
+			expected: dest,
+		},
+		{
+			desc:     "new file",
+			name:     "dir/a/b.txt",
+			expected: filepath.Join(dest, "dir", "a", "b.txt"),
+		},
+		{
+			desc:    "outside",
+			name:    "../x",
+			invalid: true,
+		},
+		{
+			desc:    "through a link",
+			name:    "link/x",
+			invalid: true,
+		},
+		{
+			desc:    "replacing a link",
+			name:    "link",
+			invalid: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			target, err := Target(dest, test.name)
+			if test.invalid {
+				if err == nil {
+					t.Errorf("got %q, want an error", target)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if target != test.expected {
+				t.Errorf("got %q, want %q", target, test.expected)
+			}
+		})
+	}
+}
+
+func TestLink(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		name     string
+		linkname string
+		invalid  bool
+	}{
+		{
+			desc:     "sibling",
+			name:     "dir/link",
+			linkname: "a.txt",
+		},
+		{
+			desc:     "parent",
+			name:     "dir/link",
+			linkname: "../b.txt",
+		},
+		{
+			desc:     "absolute",
+			name:     "link",
+			linkname: "/etc/passwd",
+			invalid:  true,
+		},
+		{
+			desc:     "outside",
+			name:     "dir/link",
+			linkname: "../../x",
+			invalid:  true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			err := Link("/dest", test.name, test.linkname)
+			if test.invalid != (err != nil) {
+				t.Errorf("got %v, invalid: %v", err, test.invalid)
+			}
+		})
+	}
+}
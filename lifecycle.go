@@ -0,0 +1,169 @@
+package ghactions
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/go-github/v71/github"
+)
+
+// Phase A phase of the action (pre, main, post).
+type Phase string
+
+// Phases.
+const (
+	PhasePre  Phase = "pre"
+	PhaseMain Phase = "main"
+	PhasePost Phase = "post"
+)
+
+// InputPhase the input defining explicitly the phase of the action (pre, main, post).
+const InputPhase = "phase"
+
+// EnvPhase the environment variable defining explicitly the phase of the action (pre, main, post),
+// e.g. set by the script of the `pre-entrypoint` or the `post-entrypoint` of action.yml.
+const EnvPhase = "GHACTIONS_PHASE"
+
+// phaseState the name of the state containing the last phase.
+const phaseState = "ghactions_phase"
+
+// PhaseHandler A handler of a phase of the action.
+type PhaseHandler func(*github.Client) error
+
+// OnPre Pre-step handler (the `pre` or `pre-entrypoint` of action.yml).
+func (a *Action) OnPre(handler PhaseHandler) *Action {
+	a.onPre = handler
+	return a
+}
+
+// OnMain Main step handler.
+// It replaces the dispatch of the event handlers.
+func (a *Action) OnMain(handler PhaseHandler) *Action {
+	a.onMain = handler
+	return a
+}
+
+// OnPost Post-step handler (the `post` or `post-entrypoint` of action.yml), e.g. a cleanup.
+func (a *Action) OnPost(handler PhaseHandler) *Action {
+	a.onPost = handler
+	return a
+}
+
+// CurrentPhase Detects the phase of the action:
+//   - the input "phase" when it is defined.
+//   - the environment variable GHACTIONS_PHASE when it is defined.
+//   - the state saved by the previous phase (STATE_*): post after main, main after pre.
+//   - main otherwise: the pre phase is never inferred, it requires an explicit signal.
+func (a *Action) CurrentPhase() Phase {
+	if phase := Phase(strings.ToLower(strings.TrimSpace(GetInput(InputPhase)))); phase != "" {
+		return phase
+	}
+
+	if phase := Phase(strings.ToLower(strings.TrimSpace(os.Getenv(EnvPhase)))); phase != "" {
+		return phase
+	}
+
+	last, _, _ := GetState[Phase](phaseState)
+
+	switch last {
+	case PhaseMain:
+		return PhasePost
+	case PhasePre:
+		return PhaseMain
+	}
+
+	return PhaseMain
+}
+
+// runPhase runs the handler of the pre and post phases, and of the main phase when OnMain is used.
+// Returns false when the event handlers must be dispatched.
+func (a *Action) runPhase(phase Phase) (bool, error) {
+	var handler PhaseHandler
+
+	switch phase {
+	case PhasePre:
+		handler = a.onPre
+	case PhaseMain:
+		handler = a.onMain
+	case PhasePost:
+		handler = a.onPost
+	default:
+		return true, fmt.Errorf("unknown phase: %q", phase)
+	}
+
+	// saved before the handler: the post phase runs even if the main phase fails.
+	err := a.savePhase(phase)
+	if err != nil {
+		return true, err
+	}
+
+	if handler == nil {
+		if phase == PhaseMain {
+			return false, nil
+		}
+
+		a.logger().Debug("no handler for the phase", "phase", phase)
+
+		return true, nil
+	}
+
+	// the event is optional (e.g. the pre phase of a local run).
+	event, _ := loadEvent()
+
+	client, err := a.clientFor(a.ctx, event)
+	if err != nil {
+		return true, fmt.Errorf("create client for the phase %q: %w", phase, err)
+	}
+
+	return true, a.callHandler(os.Getenv(GithubEventName), handler, client)
+}
+
+// savePhase saves the phase for the next phase (only when the action has several phases).
+func (a *Action) savePhase(phase Phase) error {
+	if phase == PhasePost || (phase == PhaseMain && a.onPost == nil) || os.Getenv(GithubState) == "" {
+		// the last phase, or outside a workflow.
+		return nil
+	}
+
+	return SaveState(phaseState, phase)
+}
+
+// SaveState Saves a value (encoded in JSON) for the next phases of the action (GITHUB_STATE).
+func SaveState[T any](name string, value T) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encode the state %q: %w", name, err)
+	}
+
+	delimiter, err := newDelimiter()
+	if err != nil {
+		return err
+	}
+
+	path := os.Getenv(GithubState)
+	if path == "" {
+		return fmt.Errorf("save the state %q: %s is not defined", name, GithubState)
+	}
+
+	return appendToFile(path, fmt.Sprintf("%s<<%s\n%s\n%s\n", name, delimiter, data, delimiter))
+}
+
+// GetState Returns a value saved by a previous phase with SaveState (STATE_<name>).
+// The second result is false when the state does not exist.
+func GetState[T any](name string) (T, bool, error) {
+	var value T
+
+	raw, ok := os.LookupEnv("STATE_" + name)
+	if !ok {
+		return value, false, nil
+	}
+
+	err := json.Unmarshal([]byte(raw), &value)
+	if err != nil {
+		return value, false, fmt.Errorf("decode the state %q: %w", name, err)
+	}
+
+	return value, true, nil
+}
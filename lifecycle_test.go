@@ -0,0 +1,205 @@
+package ghactions
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v71/github"
+)
+
+// loadState reads a GITHUB_STATE file and defines the STATE_* environment variables, like the runner.
+func loadState(t *testing.T, path string) {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		name, delimiter, ok := strings.Cut(scanner.Text(), "<<")
+		if !ok {
+			t.Fatalf("invalid line: %q", scanner.Text())
+		}
+
+		var lines []string
+
+		for scanner.Scan() && scanner.Text() != delimiter {
+			lines = append(lines, scanner.Text())
+		}
+
+		t.Setenv("STATE_"+name, strings.Join(lines, "\n"))
+	}
+}
+
+func TestAction_Run_phases(t *testing.T) {
+	writeEvent(t, "push", `{"ref":"refs/heads/main"}`)
+
+	type tokenState struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	var calls []string
+
+	action := NewAction(context.Background()).
+		OnPre(func(*github.Client) error {
+			calls = append(calls, "pre")
+			return SaveState("token", tokenState{ID: 42, Name: "deploy"})
+		}).
+		OnMain(func(*github.Client) error {
+			calls = append(calls, "main")
+			return nil
+		}).
+		OnPost(func(*github.Client) error {
+			calls = append(calls, "post")
+
+			token, ok, err := GetState[tokenState]("token")
+			if err != nil {
+				return err
+			}
+
+			if !ok || token.ID != 42 || token.Name != "deploy" {
+				t.Errorf("got state %+v (%v)", token, ok)
+			}
+
+			return nil
+		})
+
+	for _, expected := range []Phase{PhasePre, PhaseMain, PhasePost} {
+		statePath := filepath.Join(t.TempDir(), "state")
+		t.Setenv(GithubState, statePath)
+
+		if expected == PhasePre {
+			// the pre-entrypoint.
+			t.Setenv(EnvPhase, string(PhasePre))
+		} else {
+			t.Setenv(EnvPhase, "")
+		}
+
+		phase := action.CurrentPhase()
+		if phase != expected {
+			t.Fatalf("got phase %q, want %q", phase, expected)
+		}
+
+		err := action.Run()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if phase != PhasePost {
+			loadState(t, statePath)
+		}
+	}
+
+	if !slices.Equal(calls, []string{"pre", "main", "post"}) {
+		t.Errorf("got calls %v", calls)
+	}
+}
+
+func TestAction_Run_phaseMainDispatch(t *testing.T) {
+	writeEvent(t, "push", `{"ref":"refs/heads/main"}`)
+
+	statePath := filepath.Join(t.TempDir(), "state")
+	t.Setenv(GithubState, statePath)
+
+	var calls []string
+
+	action := NewAction(context.Background()).
+		OnPush(func(*github.Client, *github.PushEvent) error {
+			calls = append(calls, "push")
+			return nil
+		}).
+		OnPost(func(*github.Client) error {
+			calls = append(calls, "post")
+			return nil
+		})
+
+	err := action.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loadState(t, statePath)
+
+	err = action.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(calls, []string{"push", "post"}) {
+		t.Errorf("got calls %v", calls)
+	}
+}
+
+func TestAction_CurrentPhase_input(t *testing.T) {
+	t.Setenv("INPUT_PHASE", "Post")
+	t.Setenv("STATE_"+phaseState, `"pre"`)
+
+	phase := NewAction(context.Background()).CurrentPhase()
+	if phase != PhasePost {
+		t.Errorf("got %q, want %q", phase, PhasePost)
+	}
+}
+
+func TestAction_CurrentPhase(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		env      string
+		state    string
+		expected Phase
+	}{
+		{
+			desc:     "no state",
+			expected: PhaseMain,
+		},
+		{
+			desc:     "pre-entrypoint",
+			env:      "pre",
+			expected: PhasePre,
+		},
+		{
+			desc:     "post-entrypoint",
+			env:      "post",
+			state:    `"pre"`,
+			expected: PhasePost,
+		},
+		{
+			desc:     "after pre",
+			state:    `"pre"`,
+			expected: PhaseMain,
+		},
+		{
+			desc:     "after main",
+			state:    `"main"`,
+			expected: PhasePost,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Setenv(EnvPhase, test.env)
+
+			if test.state != "" {
+				t.Setenv("STATE_"+phaseState, test.state)
+			}
+
+			// a pre handler does not make the first run a pre phase.
+			action := NewAction(context.Background()).OnPre(func(*github.Client) error { return nil })
+
+			phase := action.CurrentPhase()
+			if phase != test.expected {
+				t.Errorf("got %q, want %q", phase, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,325 @@
+package ghactions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ProblemMatcher A problem matcher, with the format of the runner.
+// https://github.com/actions/toolkit/blob/main/docs/problem-matchers.md
+type ProblemMatcher struct {
+	Owner string `json:"owner"`
+	// Severity The default severity of the problems: error (default), warning, or notice.
+	Severity string           `json:"severity,omitempty"`
+	Pattern  []ProblemPattern `json:"pattern"`
+}
+
+// ProblemPattern A pattern of a problem matcher, the fields are the indexes of the groups of the regexp.
+// The patterns of a multi-line problem match consecutive lines,
+// and the last pattern can loop (the previous patterns are matched once).
+type ProblemPattern struct {
+	Regexp   string `json:"regexp"`
+	File     int    `json:"file,omitempty"`
+	FromPath int    `json:"fromPath,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Severity int    `json:"severity,omitempty"`
+	Code     int    `json:"code,omitempty"`
+	Message  int    `json:"message,omitempty"`
+	Loop     bool   `json:"loop,omitempty"`
+}
+
+// AddMatchers Registers problem matchers on the runner (::add-matcher::): the runner matches the next lines of the log.
+// The matchers are written to a JSON file inside RUNNER_TEMP.
+func AddMatchers(matchers ...ProblemMatcher) error {
+	for _, m := range matchers {
+		_, err := NewMatcher(m)
+		if err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(map[string]any{"problemMatcher": matchers})
+	if err != nil {
+		return err
+	}
+
+	dir := os.Getenv(RunnerTemp)
+	if dir == "" {
+		dir = os.TempDir()
+	}
+
+	// the file is read by the runner until the end of the job: the file is not removed.
+	file, err := os.CreateTemp(dir, "problem-matcher-*.json")
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(data)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	issueCommand("add-matcher", nil, file.Name())
+
+	return nil
+}
+
+// RemoveMatcher Unregisters a problem matcher from the runner (::remove-matcher::).
+func RemoveMatcher(owner string) {
+	issueCommand("remove-matcher", []property{{"owner", owner}}, "")
+}
+
+// Problem A problem found by a matcher.
+type Problem struct {
+	Level      AnnotationLevel
+	Message    string
+	Code       string
+	Annotation Annotation
+}
+
+// Annotate Creates the annotation of the problem.
+func (p *Problem) Annotate() {
+	Annotate(p.Level, p.Message, p.Annotation)
+}
+
+// Matcher A compiled problem matcher, to match lines in-process with the semantics of the runner.
+// A Matcher is safe for concurrent use, but the lines of a multi-line problem must be consecutive:
+// the streams of output (ex: stdout and stderr) must use different matchers (see Clone).
+type Matcher struct {
+	owner    string
+	severity string
+	patterns []*problemPattern
+
+	mu    sync.Mutex
+	index int
+	state problemValues
+}
+
+type problemPattern struct {
+	ProblemPattern
+
+	re *regexp.Regexp
+}
+
+type problemValues struct {
+	file, fromPath, line, column, severity, code, message string
+}
+
+// NewMatcher Compiles a problem matcher.
+func NewMatcher(m ProblemMatcher) (*Matcher, error) {
+	if m.Owner == "" {
+		return nil, errors.New("the owner of the problem matcher is empty")
+	}
+
+	if len(m.Pattern) == 0 {
+		return nil, fmt.Errorf("problem matcher %s: no patterns", m.Owner)
+	}
+
+	matcher := &Matcher{owner: m.Owner, severity: m.Severity}
+
+	var hasMessage bool
+
+	for i, p := range m.Pattern {
+		re, err := regexp.Compile(p.Regexp)
+		if err != nil {
+			return nil, fmt.Errorf("problem matcher %s: pattern %d: %w", m.Owner, i, err)
+		}
+
+		if p.Loop && (i != len(m.Pattern)-1 || len(m.Pattern) == 1) {
+			return nil, fmt.Errorf("problem matcher %s: only the last pattern of a multi-line matcher can loop", m.Owner)
+		}
+
+		for _, group := range []int{p.File, p.FromPath, p.Line, p.Column, p.Severity, p.Code, p.Message} {
+			if group > re.NumSubexp() {
+				return nil, fmt.Errorf("problem matcher %s: pattern %d: the group %d does not exist", m.Owner, i, group)
+			}
+		}
+
+		hasMessage = hasMessage || p.Message > 0
+
+		matcher.patterns = append(matcher.patterns, &problemPattern{ProblemPattern: p, re: re})
+	}
+
+	if !hasMessage {
+		return nil, fmt.Errorf("problem matcher %s: no pattern defines the message", m.Owner)
+	}
+
+	return matcher, nil
+}
+
+// Owner Returns the owner of the matcher.
+func (m *Matcher) Owner() string {
+	return m.owner
+}
+
+// Clone Returns a copy of the matcher without the state of a partial multi-line problem.
+func (m *Matcher) Clone() *Matcher {
+	return &Matcher{owner: m.owner, severity: m.severity, patterns: m.patterns}
+}
+
+// Match Matches a line of output, and returns the problem when the line completes a problem.
+func (m *Matcher) Match(line string) (*Problem, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	line = strings.TrimRight(line, "\r\n")
+
+	pattern := m.patterns[m.index]
+
+	groups := pattern.re.FindStringSubmatch(line)
+	if groups == nil {
+		if m.index == 0 {
+			return nil, false
+		}
+
+		// the problem is not complete: starts again with the first pattern.
+		m.reset()
+
+		return m.matchFirst(line)
+	}
+
+	if m.index < len(m.patterns)-1 {
+		m.state.apply(pattern, groups)
+		m.index++
+
+		return nil, false
+	}
+
+	values := m.state
+	values.apply(pattern, groups)
+
+	if !pattern.Loop {
+		m.reset()
+	}
+
+	return m.problem(values)
+}
+
+// Reset Forgets the lines of a partial multi-line problem.
+func (m *Matcher) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.reset()
+}
+
+func (m *Matcher) reset() {
+	m.index = 0
+	m.state = problemValues{}
+}
+
+// matchFirst matches a line with the first pattern (the lock is held).
+func (m *Matcher) matchFirst(line string) (*Problem, bool) {
+	groups := m.patterns[0].re.FindStringSubmatch(line)
+	if groups == nil {
+		return nil, false
+	}
+
+	if len(m.patterns) > 1 {
+		m.state.apply(m.patterns[0], groups)
+		m.index++
+
+		return nil, false
+	}
+
+	values := problemValues{}
+	values.apply(m.patterns[0], groups)
+
+	return m.problem(values)
+}
+
+func (m *Matcher) problem(values problemValues) (*Problem, bool) {
+	if values.message == "" {
+		return nil, false
+	}
+
+	problem := &Problem{
+		Level:   severityLevel(values.severity, m.severity),
+		Message: values.message,
+		Code:    values.code,
+		Annotation: Annotation{
+			// the code of the problem (e.g. the name of the linter rule) is the title of the annotation.
+			Title: values.code,
+			File:  problemFile(values.file, values.fromPath),
+		},
+	}
+
+	problem.Annotation.Line, _ = strconv.Atoi(values.line)
+	problem.Annotation.Col, _ = strconv.Atoi(values.column)
+
+	return problem, true
+}
+
+func (v *problemValues) apply(p *problemPattern, groups []string) {
+	for _, field := range []struct {
+		group int
+		value *string
+	}{
+		{p.File, &v.file},
+		{p.FromPath, &v.fromPath},
+		{p.Line, &v.line},
+		{p.Column, &v.column},
+		{p.Severity, &v.severity},
+		{p.Code, &v.code},
+		{p.Message, &v.message},
+	} {
+		if field.group > 0 && groups[field.group] != "" {
+			*field.value = strings.TrimSpace(groups[field.group])
+		}
+	}
+}
+
+// severityLevel converts a severity to an annotation level, the unknown severities are errors (like the runner).
+func severityLevel(severity, defaultSeverity string) AnnotationLevel {
+	if severity == "" {
+		severity = defaultSeverity
+	}
+
+	switch strings.ToLower(severity) {
+	case "warning":
+		return AnnotationWarning
+	case "notice":
+		return AnnotationNotice
+	default:
+		return AnnotationError
+	}
+}
+
+// problemFile resolves the file of a problem:
+// the relative files are relative to the directory of fromPath (when defined),
+// and the files inside GITHUB_WORKSPACE are relative to the workspace.
+func problemFile(file, fromPath string) string {
+	if file == "" {
+		return ""
+	}
+
+	if !filepath.IsAbs(file) && fromPath != "" {
+		file = filepath.Join(filepath.Dir(fromPath), file)
+	}
+
+	ws := os.Getenv(GithubWorkspace)
+	if ws == "" || !filepath.IsAbs(file) {
+		return filepath.ToSlash(filepath.Clean(file))
+	}
+
+	rel, err := filepath.Rel(ws, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(file)
+	}
+
+	return filepath.ToSlash(rel)
+}
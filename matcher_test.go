@@ -0,0 +1,291 @@
+package ghactions
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMatcher_Match(t *testing.T) {
+	t.Setenv(GithubWorkspace, "/ws")
+
+	testCases := []struct {
+		desc     string
+		matcher  ProblemMatcher
+		lines    []string
+		expected []Problem
+	}{
+		{
+			desc: "single line",
+			matcher: ProblemMatcher{
+				Owner:    "go",
+				Severity: "warning",
+				Pattern: []ProblemPattern{{
+					Regexp:  `^([^:]+):(\d+):(\d+): (.+) \((\w+)\)$`,
+					File:    1,
+					Line:    2,
+					Column:  3,
+					Message: 4,
+					Code:    5,
+				}},
+			},
+			lines: []string{
+				"/ws/main.go:12:5: unused variable (unused)",
+				"level=info msg=done",
+				"pkg/a.go:3:1: missing comment (revive)",
+			},
+			expected: []Problem{
+				{
+					Level:      AnnotationWarning,
+					Message:    "unused variable",
+					Code:       "unused",
+					Annotation: Annotation{Title: "unused", File: "main.go", Line: 12, Col: 5},
+				},
+				{
+					Level:      AnnotationWarning,
+					Message:    "missing comment",
+					Code:       "revive",
+					Annotation: Annotation{Title: "revive", File: "pkg/a.go", Line: 3, Col: 1},
+				},
+			},
+		},
+		{
+			desc: "multi-line loop",
+			matcher: ProblemMatcher{
+				Owner: "eslint-stylish",
+				Pattern: []ProblemPattern{
+					{Regexp: `^([^\s].*)$`, File: 1},
+					{Regexp: `^\s+(\d+):(\d+)\s+(error|warning|notice)\s+(.*)\s\s+(.*)$`, Line: 1, Column: 2, Severity: 3, Message: 4, Code: 5, Loop: true},
+				},
+			},
+			lines: []string{
+				"src/a.js",
+				"  1:10  error    Missing semicolon  semi",
+				"  2:1   warning  Unexpected console  no-console",
+				"src/b.js",
+				"  3:4   error    Unused  no-unused-vars",
+				"",
+				"  5:5   error    Orphan  orphan",
+			},
+			expected: []Problem{
+				{
+					Level:      AnnotationError,
+					Message:    "Missing semicolon",
+					Code:       "semi",
+					Annotation: Annotation{Title: "semi", File: "src/a.js", Line: 1, Col: 10},
+				},
+				{
+					Level:      AnnotationWarning,
+					Message:    "Unexpected console",
+					Code:       "no-console",
+					Annotation: Annotation{Title: "no-console", File: "src/a.js", Line: 2, Col: 1},
+				},
+				{
+					Level:      AnnotationError,
+					Message:    "Unused",
+					Code:       "no-unused-vars",
+					Annotation: Annotation{Title: "no-unused-vars", File: "src/b.js", Line: 3, Col: 4},
+				},
+			},
+		},
+		{
+			desc: "multi-line reset",
+			matcher: ProblemMatcher{
+				Owner: "two-lines",
+				Pattern: []ProblemPattern{
+					{Regexp: `^FILE (.+)$`, File: 1},
+					{Regexp: `^MSG (.+)$`, Message: 1},
+				},
+			},
+			lines: []string{
+				"FILE a.txt",
+				"FILE b.txt",
+				"MSG first",
+				"MSG orphan",
+				"FILE c.txt",
+				"other",
+				"MSG orphan",
+			},
+			expected: []Problem{
+				{
+					Level:      AnnotationError,
+					Message:    "first",
+					Annotation: Annotation{File: "b.txt"},
+				},
+			},
+		},
+		{
+			desc: "from path",
+			matcher: ProblemMatcher{
+				Owner: "from-path",
+				Pattern: []ProblemPattern{{
+					Regexp:   `^(\S+) (\S+):(\d+) (.+)$`,
+					FromPath: 1,
+					File:     2,
+					Line:     3,
+					Message:  4,
+				}},
+			},
+			lines: []string{"/ws/sub/project.json src/a.c:7 boom"},
+			expected: []Problem{
+				{
+					Level:      AnnotationError,
+					Message:    "boom",
+					Annotation: Annotation{File: "sub/src/a.c", Line: 7},
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			matcher, err := NewMatcher(test.matcher)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var problems []Problem
+
+			for _, line := range test.lines {
+				if problem, ok := matcher.Match(line); ok {
+					problems = append(problems, *problem)
+				}
+			}
+
+			if len(problems) != len(test.expected) {
+				t.Fatalf("got %d problems %+v, want %d", len(problems), problems, len(test.expected))
+			}
+
+			for i, problem := range problems {
+				if problem != test.expected[i] {
+					t.Errorf("problem %d: got %+v, want %+v", i, problem, test.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMatcher_Clone(t *testing.T) {
+	matcher, err := NewMatcher(ProblemMatcher{
+		Owner: "two-lines",
+		Pattern: []ProblemPattern{
+			{Regexp: `^FILE (.+)$`, File: 1},
+			{Regexp: `^MSG (.+)$`, Message: 1},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = matcher.Match("FILE a.txt")
+
+	clone := matcher.Clone()
+
+	if clone.Owner() != "two-lines" {
+		t.Errorf("got owner %q", clone.Owner())
+	}
+
+	// the clone does not have the partial problem.
+	if problem, ok := clone.Match("MSG orphan"); ok {
+		t.Errorf("got %+v, want no problem", problem)
+	}
+
+	// the state of the matcher is not changed by the clone.
+	problem, ok := matcher.Match("MSG first")
+	if !ok || problem.Annotation.File != "a.txt" {
+		t.Errorf("got %+v, want a problem in a.txt", problem)
+	}
+}
+
+func TestNewMatcher_invalid(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		matcher ProblemMatcher
+	}{
+		{
+			desc:    "no owner",
+			matcher: ProblemMatcher{Pattern: []ProblemPattern{{Regexp: `(.+)`, Message: 1}}},
+		},
+		{
+			desc:    "no patterns",
+			matcher: ProblemMatcher{Owner: "a"},
+		},
+		{
+			desc:    "invalid regexp",
+			matcher: ProblemMatcher{Owner: "a", Pattern: []ProblemPattern{{Regexp: `(`, Message: 1}}},
+		},
+		{
+			desc:    "no message",
+			matcher: ProblemMatcher{Owner: "a", Pattern: []ProblemPattern{{Regexp: `(.+)`, File: 1}}},
+		},
+		{
+			desc:    "unknown group",
+			matcher: ProblemMatcher{Owner: "a", Pattern: []ProblemPattern{{Regexp: `(.+)`, Message: 2}}},
+		},
+		{
+			desc:    "loop on a single pattern",
+			matcher: ProblemMatcher{Owner: "a", Pattern: []ProblemPattern{{Regexp: `(.+)`, Message: 1, Loop: true}}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := NewMatcher(test.matcher)
+			if err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestAddMatchers(t *testing.T) {
+	buf := captureCommands(t)
+
+	t.Setenv(RunnerTemp, t.TempDir())
+
+	matcher := ProblemMatcher{
+		Owner:   "go",
+		Pattern: []ProblemPattern{{Regexp: `^(.+):(\d+): (.+)$`, File: 1, Line: 2, Message: 3}},
+	}
+
+	err := AddMatchers(matcher)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	RemoveMatcher("go")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %q", buf.String())
+	}
+
+	path, ok := strings.CutPrefix(lines[0], "::add-matcher::")
+	if !ok || filepath.Dir(path) != os.Getenv(RunnerTemp) {
+		t.Errorf("got %q, want an add-matcher command with a file inside RUNNER_TEMP", lines[0])
+	}
+
+	if lines[1] != "::remove-matcher owner=go::" {
+		t.Errorf("got %q", lines[1])
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var content struct {
+		ProblemMatcher []ProblemMatcher `json:"problemMatcher"`
+	}
+
+	err = json.Unmarshal(data, &content)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(content.ProblemMatcher) != 1 || content.ProblemMatcher[0].Pattern[0] != matcher.Pattern[0] {
+		t.Errorf("got %s", data)
+	}
+}
@@ -0,0 +1,138 @@
+package ghactions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/google/go-github/v71/github"
+)
+
+// PanicError A panic recovered from a handler.
+type PanicError struct {
+	// EventName the name of the event of the handler.
+	EventName string
+	// Value the value given to panic.
+	Value any
+	// Stack the stack trace of the panic.
+	Stack []byte
+	// File the source file of the handler, empty when it cannot be resolved.
+	File string
+	// Line the line of the handler.
+	Line int
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic in the handler of the event %q: %v", e.EventName, e.Value)
+}
+
+// Unwrap returns the value given to panic when it is an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+
+	return nil
+}
+
+// callHandler calls a handler and converts a panic to a PanicError:
+// the stack trace is printed inside a collapsed group, and an error annotation points to the handler.
+func (a *Action) callHandler(eventName string, handler func(*github.Client) error, client *github.Client) (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		perr := &PanicError{EventName: eventName, Value: r, Stack: debug.Stack()}
+		perr.File, perr.Line = handlerLocation()
+
+		StartGroup("Stack trace of the panic")
+		writeCommandOutput(string(perr.Stack))
+		EndGroup()
+
+		Annotate(AnnotationError, perr.Error(), Annotation{
+			Title: "Panic",
+			File:  relativeToWorkspace(perr.File),
+			Line:  perr.Line,
+		})
+
+		err = perr
+	}()
+
+	return handler(client)
+}
+
+// pkgPath the import path of the package.
+var pkgPath = reflect.TypeOf(Action{}).PkgPath()
+
+// handlerLocation finds the location of the handler from the stack of a panic (must be called by the deferred function).
+// The handler is the frame before the dispatch frames of the package.
+func handlerLocation() (string, int) {
+	pcs := make([]uintptr, 100)
+	n := runtime.Callers(1, pcs)
+
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var candidate *runtime.Frame
+
+	panicked := false
+
+	for {
+		frame, more := frames.Next()
+
+		switch {
+		case !panicked:
+			// skips the frames of the recovery.
+			panicked = frame.Function == "runtime.gopanic"
+
+		case strings.HasPrefix(frame.Function, pkgPath+".(*handlers)."), frame.Function == pkgPath+".(*Action).callHandler":
+			// the dispatch of the event.
+			if candidate == nil {
+				return "", 0
+			}
+
+			return candidate.File, candidate.Line
+
+		case !isDispatchFrame(frame.Function):
+			if candidate == nil || !strings.HasPrefix(frame.Function, "runtime.") {
+				f := frame
+				candidate = &f
+			}
+		}
+
+		if !more {
+			return "", 0
+		}
+	}
+}
+
+// isDispatchFrame checks if a function is a wrapper of the handlers (e.g. OnCommand, OnWorkflowDispatchInputs).
+func isDispatchFrame(function string) bool {
+	for _, prefix := range []string{".(*Action).", ".(*command).", ".OnWorkflowDispatchInputs["} {
+		if strings.HasPrefix(function, pkgPath+prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// relativeToWorkspace returns the path relative to GITHUB_WORKSPACE when possible (the paths of the annotations are relative).
+func relativeToWorkspace(path string) string {
+	workspace := os.Getenv(GithubWorkspace)
+	if path == "" || workspace == "" {
+		return path
+	}
+
+	rel, err := filepath.Rel(workspace, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,105 @@
+package ghactions
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v71/github"
+)
+
+func TestAction_Handle_panic(t *testing.T) {
+	buf := captureCommands(t)
+
+	errBoom := errors.New("boom")
+
+	var file string
+
+	var line int
+
+	action := NewAction(context.Background()).
+		OnPush(func(*github.Client, *github.PushEvent) error {
+			_, file, line, _ = runtime.Caller(0)
+			panic(errBoom)
+		})
+
+	action.Logger = discardLogger()
+
+	_, testFile, _, _ := runtime.Caller(0)
+	t.Setenv(GithubWorkspace, filepath.Dir(testFile))
+
+	err := action.Handle(context.Background(), "push", []byte(`{"ref":"refs/heads/main"}`))
+
+	var perr *PanicError
+	if !errors.As(err, &perr) {
+		t.Fatalf("got %v, want a PanicError", err)
+	}
+
+	if !errors.Is(err, errBoom) {
+		t.Errorf("got %v, want %v", err, errBoom)
+	}
+
+	if perr.EventName != "push" {
+		t.Errorf("got event %q, want push", perr.EventName)
+	}
+
+	if perr.File != file || perr.Line != line+1 {
+		t.Errorf("got %s:%d, want %s:%d", perr.File, perr.Line, file, line+1)
+	}
+
+	output := buf.String()
+
+	if !strings.HasPrefix(output, "::group::Stack trace of the panic\n") || !strings.Contains(output, "\n::endgroup::\n") {
+		t.Errorf("missing group: %q", output)
+	}
+
+	expected := `::error title=Panic,file=panic_test.go,line=` + strconv.Itoa(line+1) +
+		`::panic in the handler of the event "push": boom` + "\n"
+
+	if !strings.HasSuffix(output, expected) {
+		t.Errorf("got %q, want suffix %q", output, expected)
+	}
+}
+
+func TestAction_Handle_panicInCommand(t *testing.T) {
+	captureCommands(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/repos/ldez/ghactions/collaborators/octocat/permission", func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte(`{"permission":"admin"}`))
+	})
+	mux.HandleFunc("POST /api/v3/repos/ldez/ghactions/issues/comments/9/reactions", func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte(`{}`))
+	})
+
+	var line int
+
+	action := newTestAction(t, mux).
+		OnCommand("/boom", func(*github.Client, *Command) error {
+			_, _, line, _ = runtime.Caller(0)
+
+			var m map[string]int
+			m["a"] = 1 // nil map.
+
+			return nil
+		})
+
+	payload := `{"action":"created","comment":{"id":9,"body":"/boom","user":{"login":"octocat"}},` +
+		`"issue":{"number":1},"repository":{"full_name":"ldez/ghactions"}}`
+
+	err := action.Handle(context.Background(), "issue_comment", []byte(payload))
+
+	var perr *PanicError
+	if !errors.As(err, &perr) {
+		t.Fatalf("got %v, want a PanicError", err)
+	}
+
+	if perr.Line != line+3 {
+		t.Errorf("got line %d, want %d", perr.Line, line+3)
+	}
+}
@@ -0,0 +1,269 @@
+package ghactions
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v71/github"
+)
+
+// Git ref prefixes.
+const (
+	refHeads      = "refs/heads/"
+	refTags       = "refs/tags/"
+	refPull       = "refs/pull/"
+	refMergeQueue = refHeads + "gh-readonly-queue/"
+)
+
+// Ref A Git ref (ex: refs/heads/main, refs/tags/v1.0.0, refs/pull/42/merge).
+type Ref struct {
+	// Name the full name of the ref (ex: refs/heads/main).
+	Name string
+	// Head the head branch of a pull request (GITHUB_HEAD_REF).
+	Head string
+	// Base the base branch of a pull request or a merge group (GITHUB_BASE_REF).
+	Base string
+	// DefaultBranch the default branch of the repository (from the event payload).
+	DefaultBranch string
+}
+
+// ParseRef Creates a Ref from a full ref name.
+// A short name is considered as a branch.
+func ParseRef(name string) Ref {
+	if name != "" && !strings.HasPrefix(name, "refs/") {
+		name = refHeads + name
+	}
+
+	return Ref{Name: name}
+}
+
+// RefFromEnv Creates a Ref from the environment variables (GITHUB_REF, GITHUB_HEAD_REF, GITHUB_BASE_REF).
+// The default branch is read from the event payload (GITHUB_EVENT_PATH) when possible.
+func RefFromEnv() Ref {
+	ref := Ref{
+		Name: os.Getenv(GithubRef),
+		Head: os.Getenv(GithubHeadRef),
+		Base: os.Getenv(GithubBaseRef),
+	}
+
+	if event, err := loadEvent(); err == nil {
+		ref.DefaultBranch = defaultBranch(event)
+	}
+
+	return ref
+}
+
+// RefFromEvent Creates a Ref from an event payload.
+// The name of the ref follows the GITHUB_REF rules: https://docs.github.com/en/actions/writing-workflows/choosing-when-your-workflow-runs/events-that-trigger-workflows
+// Ex: the default branch for delete and workflow_run.
+func RefFromEvent(event any) Ref {
+	ref := Ref{DefaultBranch: defaultBranch(event)}
+
+	switch evt := event.(type) {
+	case *github.PullRequestEvent:
+		ref.Name = fmt.Sprintf("%s%d/merge", refPull, evt.GetNumber())
+		ref.Head = evt.GetPullRequest().GetHead().GetRef()
+		ref.Base = evt.GetPullRequest().GetBase().GetRef()
+
+	case *github.PullRequestTargetEvent:
+		ref.Name = refHeads + evt.GetPullRequest().GetBase().GetRef()
+		ref.Head = evt.GetPullRequest().GetHead().GetRef()
+		ref.Base = evt.GetPullRequest().GetBase().GetRef()
+
+	case *github.PullRequestReviewEvent:
+		ref.Name = fmt.Sprintf("%s%d/merge", refPull, evt.GetPullRequest().GetNumber())
+		ref.Head = evt.GetPullRequest().GetHead().GetRef()
+		ref.Base = evt.GetPullRequest().GetBase().GetRef()
+
+	case *github.PullRequestReviewCommentEvent:
+		ref.Name = fmt.Sprintf("%s%d/merge", refPull, evt.GetPullRequest().GetNumber())
+		ref.Head = evt.GetPullRequest().GetHead().GetRef()
+		ref.Base = evt.GetPullRequest().GetBase().GetRef()
+
+	case *github.MergeGroupEvent:
+		ref.Name = evt.GetMergeGroup().GetHeadRef()
+		ref.Base = strings.TrimPrefix(evt.GetMergeGroup().GetBaseRef(), refHeads)
+
+	case *github.CreateEvent:
+		ref.Name = shortToFullRef(evt.GetRef(), evt.GetRefType())
+
+	case *github.DeleteEvent, *github.WorkflowRunEvent:
+		// GITHUB_REF is the default branch (not the deleted ref, not the branch of the workflow run).
+
+	case *github.ReleaseEvent:
+		ref.Name = refTags + evt.GetRelease().GetTagName()
+
+	case interface{ GetRef() string }:
+		ref.Name = evt.GetRef()
+	}
+
+	if ref.Name == "" && ref.DefaultBranch != "" {
+		// events on the default branch (ex: issues, schedule).
+		ref.Name = refHeads + ref.DefaultBranch
+	}
+
+	return ref
+}
+
+// IsBranch Checks if the ref is a branch (refs/heads/).
+func (r Ref) IsBranch() bool {
+	return strings.HasPrefix(r.Name, refHeads)
+}
+
+// IsTag Checks if the ref is a tag (refs/tags/).
+func (r Ref) IsTag() bool {
+	return strings.HasPrefix(r.Name, refTags)
+}
+
+// IsPullRequest Checks if the ref is a pull request ref (refs/pull/<number>/merge or refs/pull/<number>/head).
+func (r Ref) IsPullRequest() bool {
+	return strings.HasPrefix(r.Name, refPull) && r.PRNumber() > 0
+}
+
+// PRNumber Returns the number of the pull request, 0 when the ref is not a pull request ref or a merge queue ref.
+func (r Ref) PRNumber() int {
+	if strings.HasPrefix(r.Name, refPull) {
+		number, _, _ := strings.Cut(strings.TrimPrefix(r.Name, refPull), "/")
+
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			return 0
+		}
+
+		return n
+	}
+
+	if r.IsMergeGroup() {
+		if m := mergeQueueRef.FindStringSubmatch(r.Name); m != nil {
+			n, _ := strconv.Atoi(m[2])
+			return n
+		}
+	}
+
+	return 0
+}
+
+// IsMergeGroup Checks if the ref is a merge queue ref (refs/heads/gh-readonly-queue/<base>/pr-<number>-<sha>).
+func (r Ref) IsMergeGroup() bool {
+	return strings.HasPrefix(r.Name, refMergeQueue)
+}
+
+// ShortName Returns the short name of the ref (GITHUB_REF_NAME), ex: main, v1.0.0, 42/merge.
+func (r Ref) ShortName() string {
+	for _, prefix := range []string{refHeads, refTags, refPull} {
+		if strings.HasPrefix(r.Name, prefix) {
+			return strings.TrimPrefix(r.Name, prefix)
+		}
+	}
+
+	return r.Name
+}
+
+// IsDefaultBranch Checks if the ref is the default branch of the repository.
+func (r Ref) IsDefaultBranch() bool {
+	return r.DefaultBranch != "" && r.IsBranch() && r.ShortName() == r.DefaultBranch
+}
+
+// Version Parses the tag as a semantic version (with or without a "v" prefix).
+func (r Ref) Version() (*Version, error) {
+	if !r.IsTag() {
+		return nil, fmt.Errorf("not a tag: %q", r.Name)
+	}
+
+	return ParseVersion(r.ShortName())
+}
+
+// String returns the full name of the ref.
+func (r Ref) String() string {
+	return r.Name
+}
+
+// mergeQueueRef matches refs/heads/gh-readonly-queue/<base>/pr-<number>-<sha>.
+var mergeQueueRef = regexp.MustCompile(`^refs/heads/gh-readonly-queue/(.+)/pr-(\d+)-[0-9a-f]+$`)
+
+// semverPattern https://semver.org/#is-there-a-suggested-regular-expression-regex-to-check-a-semver-string
+var semverPattern = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// Version A semantic version.
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+	Build      string
+	// Original the parsed string.
+	Original string
+}
+
+// ParseVersion Parses a semantic version (with or without a "v" prefix).
+func ParseVersion(s string) (*Version, error) {
+	m := semverPattern.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("invalid semantic version: %q", s)
+	}
+
+	v := &Version{Prerelease: m[4], Build: m[5], Original: s}
+
+	var err error
+
+	for i, target := range []*int{&v.Major, &v.Minor, &v.Patch} {
+		*target, err = strconv.Atoi(m[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid semantic version: %q: %w", s, err)
+		}
+	}
+
+	return v, nil
+}
+
+// IsPrerelease Checks if the version is a pre-release.
+func (v *Version) IsPrerelease() bool {
+	return v.Prerelease != ""
+}
+
+// String returns the version without the "v" prefix.
+func (v *Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+
+	return s
+}
+
+func shortToFullRef(name, refType string) string {
+	switch refType {
+	case "tag":
+		return refTags + name
+	case "branch":
+		return refHeads + name
+	default:
+		return name
+	}
+}
+
+// defaultBranch finds the default branch of the repository of an event.
+func defaultBranch(event any) string {
+	switch evt := event.(type) {
+	case interface {
+		GetRepo() *github.PushEventRepository
+	}:
+		return evt.GetRepo().GetDefaultBranch()
+	case interface{ GetRepo() *github.Repository }:
+		return evt.GetRepo().GetDefaultBranch()
+	case interface{ GetRepository() *github.Repository }:
+		return evt.GetRepository().GetDefaultBranch()
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,161 @@
+package ghactions
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v71/github"
+)
+
+func TestRef(t *testing.T) {
+	testCases := []struct {
+		ref           string
+		branch        bool
+		tag           bool
+		pullRequest   bool
+		mergeGroup    bool
+		prNumber      int
+		shortName     string
+		defaultBranch bool
+	}{
+		{ref: "refs/heads/master", branch: true, shortName: "master", defaultBranch: true},
+		{ref: "refs/heads/feat/ref", branch: true, shortName: "feat/ref"},
+		{ref: "refs/tags/v1.2.3", tag: true, shortName: "v1.2.3"},
+		{ref: "refs/pull/42/merge", pullRequest: true, prNumber: 42, shortName: "42/merge"},
+		{
+			ref:        "refs/heads/gh-readonly-queue/master/pr-12-0123456789abcdef0123456789abcdef01234567",
+			branch:     true,
+			mergeGroup: true,
+			prNumber:   12,
+			shortName:  "gh-readonly-queue/master/pr-12-0123456789abcdef0123456789abcdef01234567",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.ref, func(t *testing.T) {
+			ref := ParseRef(test.ref)
+			ref.DefaultBranch = "master"
+
+			if ref.IsBranch() != test.branch {
+				t.Errorf("IsBranch: got %v", ref.IsBranch())
+			}
+			if ref.IsTag() != test.tag {
+				t.Errorf("IsTag: got %v", ref.IsTag())
+			}
+			if ref.IsPullRequest() != test.pullRequest {
+				t.Errorf("IsPullRequest: got %v", ref.IsPullRequest())
+			}
+			if ref.IsMergeGroup() != test.mergeGroup {
+				t.Errorf("IsMergeGroup: got %v", ref.IsMergeGroup())
+			}
+			if ref.PRNumber() != test.prNumber {
+				t.Errorf("PRNumber: got %d, want %d", ref.PRNumber(), test.prNumber)
+			}
+			if ref.ShortName() != test.shortName {
+				t.Errorf("ShortName: got %q, want %q", ref.ShortName(), test.shortName)
+			}
+			if ref.IsDefaultBranch() != test.defaultBranch {
+				t.Errorf("IsDefaultBranch: got %v", ref.IsDefaultBranch())
+			}
+		})
+	}
+}
+
+func TestRefFromEnv(t *testing.T) {
+	t.Setenv(GithubRef, "refs/heads/master")
+	t.Setenv(GithubEventName, "push")
+	t.Setenv(GithubEventPath, "./fixtures/issues.json")
+
+	ref := RefFromEnv()
+
+	if !ref.IsDefaultBranch() {
+		t.Errorf("expected the default branch: %+v", ref)
+	}
+}
+
+func TestRefFromEvent(t *testing.T) {
+	event := &github.PullRequestEvent{
+		Number: github.Ptr(7),
+		PullRequest: &github.PullRequest{
+			Head: &github.PullRequestBranch{Ref: github.Ptr("feat")},
+			Base: &github.PullRequestBranch{Ref: github.Ptr("main")},
+		},
+		Repo: &github.Repository{DefaultBranch: github.Ptr("main")},
+	}
+
+	ref := RefFromEvent(event)
+
+	expected := Ref{Name: "refs/pull/7/merge", Head: "feat", Base: "main", DefaultBranch: "main"}
+	if ref != expected {
+		t.Errorf("got %+v, want %+v", ref, expected)
+	}
+
+	repo := &github.Repository{DefaultBranch: github.Ptr("main")}
+
+	testCases := []struct {
+		desc     string
+		event    any
+		expected string
+	}{
+		{
+			desc:     "create",
+			event:    &github.CreateEvent{Ref: github.Ptr("v1.0.0"), RefType: github.Ptr("tag"), Repo: repo},
+			expected: "refs/tags/v1.0.0",
+		},
+		{
+			desc:     "delete",
+			event:    &github.DeleteEvent{Ref: github.Ptr("feat"), RefType: github.Ptr("branch"), Repo: repo},
+			expected: "refs/heads/main",
+		},
+		{
+			desc:     "workflow_run",
+			event:    &github.WorkflowRunEvent{WorkflowRun: &github.WorkflowRun{HeadBranch: github.Ptr("feat")}, Repo: repo},
+			expected: "refs/heads/main",
+		},
+		{
+			desc:     "release",
+			event:    &github.ReleaseEvent{Release: &github.RepositoryRelease{TagName: github.Ptr("v2.0.0")}, Repo: repo},
+			expected: "refs/tags/v2.0.0",
+		},
+		{
+			desc:     "issues",
+			event:    &github.IssuesEvent{Repo: repo},
+			expected: "refs/heads/main",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			ref := RefFromEvent(test.event)
+			if ref.Name != test.expected {
+				t.Errorf("got %q, want %q", ref.Name, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	ref := ParseRef("refs/tags/v1.2.3-rc.1+build.5")
+
+	version, err := ref.Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if version.Major != 1 || version.Minor != 2 || version.Patch != 3 || !version.IsPrerelease() || version.Build != "build.5" {
+		t.Errorf("unexpected version: %+v", version)
+	}
+
+	if version.String() != "1.2.3-rc.1+build.5" {
+		t.Errorf("got %q", version.String())
+	}
+
+	_, err = ParseVersion("v1.2")
+	if err == nil {
+		t.Error("expected an error")
+	}
+
+	_, err = ParseRef("refs/heads/main").Version()
+	if err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,149 @@
+package ghactions
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-github/v71/github"
+)
+
+// Repo A GitHub repository.
+type Repo struct {
+	Owner string
+	Name  string
+}
+
+// FullName returns the full name of the repository: owner/name.
+func (r Repo) FullName() string {
+	return r.Owner + "/" + r.Name
+}
+
+// String returns the full name of the repository.
+func (r Repo) String() string {
+	return r.FullName()
+}
+
+// ParseRepo Parses a repository full name (owner/name).
+func ParseRepo(fullName string) (Repo, error) {
+	owner, name, ok := strings.Cut(strings.TrimSpace(fullName), "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return Repo{}, fmt.Errorf("invalid repository full name: %q", fullName)
+	}
+
+	return Repo{Owner: owner, Name: name}, nil
+}
+
+// GetRepo Finds the current repository from:
+//   - the GITHUB_REPOSITORY environment variable,
+//   - the repository of the event payload (GITHUB_EVENT_PATH),
+//   - the remote "origin" of the Git repository in GITHUB_WORKSPACE (or the current directory).
+func GetRepo() (Repo, error) {
+	var errs []error
+
+	repo, err := ParseRepo(os.Getenv(GithubRepository))
+	if err == nil {
+		return repo, nil
+	}
+
+	errs = append(errs, fmt.Errorf("%s: %w", GithubRepository, err))
+
+	repo, err = repoFromEventFile()
+	if err == nil {
+		return repo, nil
+	}
+
+	errs = append(errs, fmt.Errorf("event payload: %w", err))
+
+	repo, err = repoFromGitRemote(os.Getenv(GithubWorkspace))
+	if err == nil {
+		return repo, nil
+	}
+
+	errs = append(errs, fmt.Errorf("git remote: %w", err))
+
+	return Repo{}, fmt.Errorf("unable to find the repository: %w", errors.Join(errs...))
+}
+
+// RepoFromEvent Finds the repository of an event payload.
+func RepoFromEvent(event any) (Repo, error) {
+	var fullName string
+
+	switch evt := event.(type) {
+	case interface {
+		GetRepo() *github.PushEventRepository
+	}:
+		fullName = evt.GetRepo().GetFullName()
+	case interface{ GetRepo() *github.Repository }:
+		fullName = evt.GetRepo().GetFullName()
+	case interface{ GetRepository() *github.Repository }:
+		fullName = evt.GetRepository().GetFullName()
+	default:
+		return Repo{}, fmt.Errorf("no repository in %T", event)
+	}
+
+	if fullName == "" {
+		return Repo{}, fmt.Errorf("no repository in %T", event)
+	}
+
+	return ParseRepo(fullName)
+}
+
+// repoFor the repository of the event given to a handler, or the current repository (GetRepo) without event.
+func repoFor(event any) (Repo, error) {
+	if event == nil {
+		return GetRepo()
+	}
+
+	return RepoFromEvent(event)
+}
+
+func repoFromEventFile() (Repo, error) {
+	event, err := loadEvent()
+	if err != nil {
+		return Repo{}, err
+	}
+
+	return RepoFromEvent(event)
+}
+
+func repoFromGitRemote(dir string) (Repo, error) {
+	if dir == "" {
+		dir = "."
+	}
+
+	cmd := exec.Command("git", "-C", filepath.Clean(dir), "config", "--get", "remote.origin.url")
+
+	output, err := cmd.Output()
+	if err != nil {
+		return Repo{}, fmt.Errorf("read the URL of the remote origin: %w", err)
+	}
+
+	return parseRemoteURL(strings.TrimSpace(string(output)))
+}
+
+// parseRemoteURL parses the URL of a Git remote:
+// https://github.com/owner/name.git, git@github.com:owner/name.git, ssh://git@github.com/owner/name.git.
+func parseRemoteURL(remote string) (Repo, error) {
+	path := remote
+
+	if u, err := url.Parse(remote); err == nil && u.Scheme != "" && u.Host != "" {
+		path = u.Path
+	} else if _, after, ok := strings.Cut(remote, ":"); ok && !strings.Contains(remote, "://") {
+		// scp-like syntax.
+		path = after
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+
+	repo, err := ParseRepo(path)
+	if err != nil {
+		return Repo{}, fmt.Errorf("invalid remote URL %q", remote)
+	}
+
+	return repo, nil
+}
@@ -0,0 +1,104 @@
+package ghactions
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestGetRepo(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		repository string
+		eventPath  string
+		expected   Repo
+	}{
+		{
+			desc:       "env",
+			repository: "ldez/ghactions",
+			expected:   Repo{Owner: "ldez", Name: "ghactions"},
+		},
+		{
+			desc:      "event payload",
+			eventPath: "./fixtures/issues.json",
+			expected:  Repo{Owner: "ldez", Name: "go-actions"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Setenv(GithubRepository, test.repository)
+			t.Setenv(GithubEventName, "push")
+			t.Setenv(GithubEventPath, test.eventPath)
+
+			repo, err := GetRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if repo != test.expected {
+				t.Errorf("got %+v, want %+v", repo, test.expected)
+			}
+		})
+	}
+}
+
+func TestGetRepo_gitRemote(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, args := range [][]string{{"init", "-q"}, {"remote", "add", "origin", "git@github.com:ldez/ghactions.git"}} {
+		err := exec.Command("git", append([]string{"-C", dir}, args...)...).Run()
+		if err != nil {
+			t.Skipf("git: %v", err)
+		}
+	}
+
+	t.Setenv(GithubRepository, "")
+	t.Setenv(GithubEventPath, "")
+	t.Setenv(GithubWorkspace, dir)
+
+	repo, err := GetRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if repo.FullName() != "ldez/ghactions" {
+		t.Errorf("got %q, want ldez/ghactions", repo.FullName())
+	}
+}
+
+func TestGetRepo_error(t *testing.T) {
+	t.Setenv(GithubRepository, "")
+	t.Setenv(GithubEventPath, "")
+	t.Setenv(GithubWorkspace, t.TempDir())
+
+	_, err := GetRepo()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	owner, name := GetRepoInfo()
+	if owner != "" || name != "" {
+		t.Errorf("got %q/%q, want empty strings", owner, name)
+	}
+}
+
+func TestParseRemoteURL(t *testing.T) {
+	testCases := []string{
+		"https://github.com/ldez/ghactions.git",
+		"https://github.com/ldez/ghactions",
+		"git@github.com:ldez/ghactions.git",
+		"ssh://git@github.com/ldez/ghactions.git",
+	}
+
+	for _, remote := range testCases {
+		repo, err := parseRemoteURL(remote)
+		if err != nil {
+			t.Errorf("%s: %v", remote, err)
+			continue
+		}
+
+		if repo.FullName() != "ldez/ghactions" {
+			t.Errorf("%s: got %q", remote, repo.FullName())
+		}
+	}
+}
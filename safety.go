@@ -0,0 +1,279 @@
+package ghactions
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/ldez/ghactions/internal/safepath"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnsafePullRequestTarget A safety check of pull_request_target failed (see StrictPullRequestTarget).
+var ErrUnsafePullRequestTarget = errors.New("unsafe pull_request_target")
+
+// SafetyCheck A safety check of the pull_request_target events.
+type SafetyCheck string
+
+// Safety checks.
+const (
+	// CheckUntrustedCheckout the workspace contains the code of a pull request from a fork.
+	CheckUntrustedCheckout SafetyCheck = "untrusted-checkout"
+	// CheckWriteToken the token has write permissions.
+	CheckWriteToken SafetyCheck = "write-token"
+	// CheckUntrustedRead the handler reads a file from the untrusted checkout (see ReadWorkspaceFile).
+	CheckUntrustedRead SafetyCheck = "untrusted-read"
+)
+
+// AllowUnsafePullRequestTarget Disables safety checks of pull_request_target for the intentional cases.
+// The reason is logged when a disabled check would have been reported.
+func (a *Action) AllowUnsafePullRequestTarget(reason string, checks ...SafetyCheck) *Action {
+	if a.allowedChecks == nil {
+		a.allowedChecks = map[SafetyCheck]string{}
+	}
+
+	for _, check := range checks {
+		a.allowedChecks[check] = reason
+	}
+
+	return a
+}
+
+// checkPullRequestTarget runs the safety checks before the dispatch of a pull_request_target event:
+// a pull request from a fork checked out with a privileged token is a well-known exploit.
+// The checks are about the workspace and the token of a workflow run: they are skipped outside GitHub Actions (e.g. webhooks).
+func (a *Action) checkPullRequestTarget(ctx context.Context, client *github.Client, event any) error {
+	evt, ok := event.(*github.PullRequestTargetEvent)
+	if !ok {
+		return nil
+	}
+
+	if os.Getenv(GithubActions) != "true" {
+		a.logger().Debug("pull_request_target safety checks skipped outside GitHub Actions")
+		return nil
+	}
+
+	var errs []error
+
+	untrusted := untrustedCheckout(ctx, evt)
+	if untrusted {
+		errs = append(errs, a.reportUnsafe(CheckUntrustedCheckout,
+			"the workspace contains the code of a pull request from a fork (HEAD is the head of the pull request)"))
+	}
+
+	reason, err := writeToken(ctx, client)
+	if err != nil {
+		// unknown permissions are unsafe.
+		reason = fmt.Sprintf("unable to check the permissions of the token: %v", err)
+	}
+
+	if reason != "" {
+		errs = append(errs, a.reportUnsafe(CheckWriteToken, reason))
+	}
+
+	return errors.Join(errs...)
+}
+
+// ReadWorkspaceFile Reads a file of GITHUB_WORKSPACE, the event is the event given to the handler (Run or Handle).
+// The paths outside the workspace (including through the links) are refused.
+// For a pull_request_target event, reading from the checkout of a pull request from a fork is reported as unsafe
+// (the file is controlled by the author of the pull request).
+func (a *Action) ReadWorkspaceFile(ctx context.Context, event any, name string) ([]byte, error) {
+	path, err := workspacePath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if evt, ok := event.(*github.PullRequestTargetEvent); ok && untrustedCheckout(ctx, evt) {
+		err = a.reportUnsafe(CheckUntrustedRead, fmt.Sprintf("the file %s is read from the checkout of a pull request from a fork", name))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return os.ReadFile(path)
+}
+
+// workspacePath returns the path of a file of GITHUB_WORKSPACE, the links are resolved.
+func workspacePath(name string) (string, error) {
+	workspace, err := filepath.Abs(os.Getenv(GithubWorkspace))
+	if err != nil {
+		return "", err
+	}
+
+	workspace, err = filepath.EvalSymlinks(workspace)
+	if err != nil {
+		return "", fmt.Errorf("resolve the workspace: %w", err)
+	}
+
+	path, err := filepath.EvalSymlinks(filepath.Join(workspace, name))
+	if err != nil {
+		return "", err
+	}
+
+	if !safepath.Within(workspace, path) {
+		return "", fmt.Errorf("the file %s is outside the workspace", name)
+	}
+
+	return path, nil
+}
+
+// reportUnsafe reports a failed check: an error with StrictPullRequestTarget, a warning otherwise.
+func (a *Action) reportUnsafe(check SafetyCheck, message string) error {
+	if reason, ok := a.allowedChecks[check]; ok {
+		a.logger().Debug("unsafe pull_request_target allowed", "check", check, "message", message, "reason", reason)
+		return nil
+	}
+
+	annotation := Annotation{Title: "Unsafe pull_request_target (" + string(check) + ")"}
+
+	if a.StrictPullRequestTarget {
+		Annotate(AnnotationError, message, annotation)
+		return fmt.Errorf("%w: %s", ErrUnsafePullRequestTarget, message)
+	}
+
+	Annotate(AnnotationWarning, message, annotation)
+
+	return nil
+}
+
+// untrustedCheckout checks if the pull request comes from a fork and is checked out in the workspace.
+func untrustedCheckout(ctx context.Context, evt *github.PullRequestTargetEvent) bool {
+	pr := evt.GetPullRequest()
+
+	head := pr.GetHead().GetRepo().GetFullName()
+	if head == "" || strings.EqualFold(head, pr.GetBase().GetRepo().GetFullName()) {
+		return false
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "-C", filepath.Clean(os.Getenv(GithubWorkspace)), "rev-parse", "HEAD")
+
+	output, err := cmd.Output()
+	if err != nil {
+		// not a Git checkout.
+		return false
+	}
+
+	return string(bytes.TrimSpace(output)) == pr.GetHead().GetSHA()
+}
+
+// writeScopes the scopes of the classic tokens allowing writes.
+var writeScopes = []string{"repo", "public_repo", "workflow", "write:packages", "write:org", "admin:org", "admin:repo_hook"}
+
+// writeToken checks if the token has write permissions.
+// The scopes of a classic token are read from the API,
+// the permissions of GITHUB_TOKEN are read from the workflow file (fetched from the API, see fetchWorkflowFile).
+func writeToken(ctx context.Context, client *github.Client) (string, error) {
+	_, resp, err := client.RateLimit.Get(ctx)
+	if err == nil && resp.Header.Get("X-Oauth-Scopes") != "" {
+		for _, scope := range strings.Split(resp.Header.Get("X-Oauth-Scopes"), ",") {
+			if slices.Contains(writeScopes, strings.TrimSpace(scope)) {
+				return fmt.Sprintf("the token has the write scope %q", strings.TrimSpace(scope)), nil
+			}
+		}
+
+		return "", nil
+	}
+
+	path, content, err := fetchWorkflowFile(ctx, client)
+	if err != nil {
+		return "", err
+	}
+
+	permissions, err := readTokenPermissions(path, content, os.Getenv(GithubJob))
+	if err != nil {
+		return "", err
+	}
+
+	switch p := permissions.(type) {
+	case nil:
+		return "the permissions of the GITHUB_TOKEN are not defined in the workflow (the default permissions can allow writes)", nil
+
+	case string:
+		if p == "write-all" {
+			return "the GITHUB_TOKEN has the permissions write-all", nil
+		}
+
+	case map[string]any:
+		var scopes []string
+
+		for scope, level := range p {
+			if level == "write" {
+				scopes = append(scopes, scope)
+			}
+		}
+
+		if len(scopes) > 0 {
+			slices.Sort(scopes)
+			return fmt.Sprintf("the GITHUB_TOKEN has write permissions: %s", strings.Join(scopes, ", ")), nil
+		}
+	}
+
+	return "", nil
+}
+
+// fetchWorkflowFile fetches the workflow file from the API, at GITHUB_WORKFLOW_REF and GITHUB_WORKFLOW_SHA:
+// the workspace can contain the checkout of a pull request (the workflow file is controlled by its author).
+func fetchWorkflowFile(ctx context.Context, client *github.Client) (string, []byte, error) {
+	ref := os.Getenv(GithubWorkflowRef)
+
+	// ex: owner/repo/.github/workflows/ci.yml@refs/heads/main
+	fullName, path, ok := strings.Cut(ref, "/.github/workflows/")
+	if !ok {
+		return "", nil, fmt.Errorf("unable to find the workflow file: invalid %s %q", GithubWorkflowRef, ref)
+	}
+
+	path, gitRef, _ := strings.Cut(path, "@")
+
+	sha := os.Getenv(GithubWorkflowSha)
+	if sha == "" {
+		sha = gitRef
+	}
+
+	owner, name, ok := strings.Cut(fullName, "/")
+	if !ok || sha == "" {
+		return "", nil, fmt.Errorf("unable to find the workflow file: invalid %s %q", GithubWorkflowRef, ref)
+	}
+
+	path = ".github/workflows/" + path
+
+	file, _, _, err := client.Repositories.GetContents(ctx, owner, name, path, &github.RepositoryContentGetOptions{Ref: sha})
+	if err != nil {
+		return "", nil, fmt.Errorf("get the workflow file %s/%s@%s: %w", fullName, path, sha, err)
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		return "", nil, fmt.Errorf("decode the workflow file %s/%s@%s: %w", fullName, path, sha, err)
+	}
+
+	return path, []byte(content), nil
+}
+
+// readTokenPermissions reads the permissions of a job, or of the workflow.
+func readTokenPermissions(workflowPath string, content []byte, job string) (any, error) {
+	var workflow struct {
+		Permissions any `yaml:"permissions"`
+		Jobs        map[string]struct {
+			Permissions any `yaml:"permissions"`
+		} `yaml:"jobs"`
+	}
+
+	err := yaml.Unmarshal(content, &workflow)
+	if err != nil {
+		return nil, fmt.Errorf("parse workflow file %s: %w", workflowPath, err)
+	}
+
+	if j, ok := workflow.Jobs[job]; ok && j.Permissions != nil {
+		return j.Permissions, nil
+	}
+
+	return workflow.Permissions, nil
+}
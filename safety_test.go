@@ -0,0 +1,286 @@
+package ghactions
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v71/github"
+)
+
+// setupWorkspace creates a Git workspace with a workflow file, and returns the SHA of HEAD.
+func setupWorkspace(t *testing.T, workflow string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	git := func(args ...string) string {
+		t.Helper()
+
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+
+		output, err := cmd.Output()
+		if err != nil {
+			t.Skipf("git %s: %v", strings.Join(args, " "), err)
+		}
+
+		return strings.TrimSpace(string(output))
+	}
+
+	err := os.MkdirAll(filepath.Join(dir, ".github", "workflows"), 0o750)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, filepath.Join(dir, ".github", "workflows", "prt.yml"), workflow)
+	writeFile(t, filepath.Join(dir, "config.txt"), "untrusted\n")
+
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-q", "-m", "first")
+
+	t.Setenv(GithubActions, "true")
+	t.Setenv(GithubWorkspace, dir)
+	t.Setenv(GithubWorkflowRef, "ldez/ghactions/.github/workflows/prt.yml@refs/heads/main")
+	t.Setenv(GithubWorkflowSha, "0123456789abcdef0123456789abcdef01234567")
+	t.Setenv(GithubJob, "test")
+
+	return git("rev-parse", "HEAD")
+}
+
+func prTargetPayload(headRepo, sha string) string {
+	return fmt.Sprintf(`{"action":"opened","sender":{"login":"stranger"},"repository":{"full_name":"ldez/ghactions"},`+
+		`"pull_request":{"number":1,"head":{"sha":%q,"repo":{"full_name":%q}},"base":{"repo":{"full_name":"ldez/ghactions"}}}}`, sha, headRepo)
+}
+
+func TestAction_checkPullRequestTarget(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		workflow string // the workflow file at GITHUB_WORKFLOW_SHA, not found when empty.
+		headRepo string
+		scopes   string
+		webhook  bool
+		strict   bool
+		allow    []SafetyCheck
+		called   bool
+		expected []string
+	}{
+		{
+			desc:     "fork checkout with a write token",
+			workflow: "permissions:\n  contents: read\njobs:\n  test:\n    permissions:\n      contents: write\n      issues: write\n",
+			headRepo: "stranger/ghactions",
+			called:   true,
+			expected: []string{
+				"::warning title=Unsafe pull_request_target (untrusted-checkout)::",
+				"::warning title=Unsafe pull_request_target (write-token)::the GITHUB_TOKEN has write permissions: contents, issues",
+			},
+		},
+		{
+			desc:     "strict",
+			workflow: "permissions: write-all\n",
+			headRepo: "stranger/ghactions",
+			strict:   true,
+			expected: []string{
+				"::error title=Unsafe pull_request_target (untrusted-checkout)::",
+				"::error title=Unsafe pull_request_target (write-token)::the GITHUB_TOKEN has the permissions write-all",
+			},
+		},
+		{
+			desc:     "strict with opt-in",
+			workflow: "permissions: write-all\n",
+			headRepo: "stranger/ghactions",
+			strict:   true,
+			allow:    []SafetyCheck{CheckUntrustedCheckout, CheckWriteToken},
+			called:   true,
+		},
+		{
+			desc:     "same repository and read token",
+			workflow: "permissions:\n  contents: read\n",
+			headRepo: "ldez/ghactions",
+			strict:   true,
+			called:   true,
+		},
+		{
+			desc:     "undefined permissions",
+			workflow: "on: pull_request_target\n",
+			headRepo: "ldez/ghactions",
+			called:   true,
+			expected: []string{
+				"::warning title=Unsafe pull_request_target (write-token)::the permissions of the GITHUB_TOKEN are not defined",
+			},
+		},
+		{
+			desc:     "unknown permissions",
+			headRepo: "ldez/ghactions",
+			strict:   true,
+			expected: []string{
+				"::error title=Unsafe pull_request_target (write-token)::unable to check the permissions of the token",
+			},
+		},
+		{
+			desc:     "outside GitHub Actions",
+			headRepo: "stranger/ghactions",
+			webhook:  true,
+			strict:   true,
+			called:   true,
+		},
+		{
+			desc:     "classic token",
+			workflow: "permissions: read-all\n",
+			headRepo: "ldez/ghactions",
+			scopes:   "read:org, repo",
+			called:   true,
+			expected: []string{
+				`::warning title=Unsafe pull_request_target (write-token)::the token has the write scope "repo"`,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			// the workflow file of the workspace is controlled by the author of the pull request.
+			sha := setupWorkspace(t, "permissions: read-all\n")
+			buf := captureCommands(t)
+
+			if test.webhook {
+				t.Setenv(GithubActions, "")
+			}
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v3/rate_limit", func(rw http.ResponseWriter, _ *http.Request) {
+				if test.webhook {
+					t.Error("unexpected API call")
+				}
+
+				if test.scopes != "" {
+					rw.Header().Set("X-OAuth-Scopes", test.scopes)
+				}
+
+				_, _ = rw.Write([]byte(`{}`))
+			})
+			mux.HandleFunc("GET /api/v3/repos/ldez/ghactions/contents/.github/workflows/prt.yml", func(rw http.ResponseWriter, req *http.Request) {
+				if test.workflow == "" || req.URL.Query().Get("ref") != os.Getenv(GithubWorkflowSha) {
+					http.Error(rw, `{"message":"Not Found"}`, http.StatusNotFound)
+					return
+				}
+
+				_ = json.NewEncoder(rw).Encode(map[string]string{
+					"type":     "file",
+					"encoding": "base64",
+					"content":  base64.StdEncoding.EncodeToString([]byte(test.workflow)),
+				})
+			})
+
+			var called bool
+
+			action := newTestAction(t, mux).
+				OnPullRequestTarget(func(*github.Client, *github.PullRequestTargetEvent) error {
+					called = true
+					return nil
+				})
+
+			action.StrictPullRequestTarget = test.strict
+
+			if len(test.allow) > 0 {
+				action.AllowUnsafePullRequestTarget("reviewed", test.allow...)
+			}
+
+			err := action.Handle(context.Background(), "pull_request_target", []byte(prTargetPayload(test.headRepo, sha)))
+
+			if test.strict && !test.called {
+				if !errors.Is(err, ErrUnsafePullRequestTarget) {
+					t.Errorf("got %v, want %v", err, ErrUnsafePullRequestTarget)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			if called != test.called {
+				t.Errorf("got called=%v, want %v", called, test.called)
+			}
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if len(test.expected) == 0 {
+				if buf.Len() > 0 {
+					t.Errorf("unexpected annotations: %q", buf.String())
+				}
+
+				return
+			}
+
+			if len(lines) != len(test.expected) {
+				t.Fatalf("got %q, want %q", lines, test.expected)
+			}
+
+			for i, prefix := range test.expected {
+				if !strings.HasPrefix(lines[i], prefix) {
+					t.Errorf("got %q, want prefix %q", lines[i], prefix)
+				}
+			}
+		})
+	}
+}
+
+func TestAction_ReadWorkspaceFile(t *testing.T) {
+	sha := setupWorkspace(t, "permissions: {}\n")
+
+	event := &github.PullRequestTargetEvent{}
+	unmarshal(t, prTargetPayload("stranger/ghactions", sha), event)
+
+	t.Run("warning", func(t *testing.T) {
+		buf := captureCommands(t)
+
+		content, err := NewAction(context.Background()).ReadWorkspaceFile(context.Background(), event, "config.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(content) != "untrusted\n" {
+			t.Errorf("got %q", content)
+		}
+
+		if !strings.HasPrefix(buf.String(), "::warning title=Unsafe pull_request_target (untrusted-read)::") {
+			t.Errorf("got %q", buf.String())
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		captureCommands(t)
+
+		action := NewAction(context.Background())
+		action.StrictPullRequestTarget = true
+
+		_, err := action.ReadWorkspaceFile(context.Background(), event, "config.txt")
+		if !errors.Is(err, ErrUnsafePullRequestTarget) {
+			t.Errorf("got %v, want %v", err, ErrUnsafePullRequestTarget)
+		}
+	})
+	t.Run("outside", func(t *testing.T) {
+		captureCommands(t)
+
+		workspace := os.Getenv(GithubWorkspace)
+
+		secret := filepath.Join(filepath.Dir(workspace), "secret")
+		writeFile(t, secret, "secret\n")
+
+		err := os.Symlink(secret, filepath.Join(workspace, "link"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, name := range []string{"../secret", "link"} {
+			content, err := NewAction(context.Background()).ReadWorkspaceFile(context.Background(), nil, name)
+			if err == nil {
+				t.Errorf("%s: got %q, want an error", name, content)
+			}
+		}
+	})
+}
@@ -0,0 +1,302 @@
+package ghactions
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// logGroup the group of the log opened by a LogHandler (the groups of the log cannot be nested).
+var logGroup struct {
+	sync.Mutex
+
+	title string
+}
+
+// LogHandler A slog.Handler writing the records as workflow commands:
+//   - the levels are mapped to ::debug:: (debug), ::notice:: (info), ::warning:: (warn), and ::error:: (error),
+//   - the attributes title, file, line, endLine, col, and endColumn are the properties of the annotations,
+//   - the other attributes are appended to the message (key=value),
+//   - the group attributes (slog.Group) are written after the message inside a group of the log (::group::), one line per attribute,
+//     the nested groups are flattened (a.b=value) and the debug records keep them in the message,
+//   - the records of a logger with groups (Logger.WithGroup) are written inside a group of the log (::group::).
+//
+// The consecutive records of the same group share the same group of the log.
+type LogHandler struct {
+	opts   slog.HandlerOptions
+	attrs  []slog.Attr
+	groups []string
+}
+
+// NewLogHandler Creates a LogHandler.
+// The minimum level is debug by default: the debug messages are only visible when the debug logging is enabled.
+func NewLogHandler(opts *slog.HandlerOptions) *LogHandler {
+	h := &LogHandler{}
+	if opts != nil {
+		h.opts = *opts
+	}
+
+	return h
+}
+
+// Enabled implements slog.Handler.
+func (h *LogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelDebug
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+
+	return level >= minLevel
+}
+
+// WithAttrs implements slog.Handler.
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(slices.Clip(h.attrs), attrs...)
+
+	return &clone
+}
+
+// WithGroup implements slog.Handler.
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	clone := *h
+	clone.groups = append(slices.Clip(h.groups), name)
+
+	return &clone
+}
+
+// logBlock the lines of a group attribute, written inside a group of the log.
+type logBlock struct {
+	title string
+	lines []string
+}
+
+// Handle implements slog.Handler.
+func (h *LogHandler) Handle(_ context.Context, record slog.Record) error {
+	name := logCommand(record.Level)
+	withProps := name != "debug"
+
+	var (
+		annotation Annotation
+		text       strings.Builder
+		blocks     []logBlock
+	)
+
+	text.WriteString(record.Message)
+
+	add := func(a slog.Attr) bool {
+		a.Value = a.Value.Resolve()
+
+		if withProps && a.Value.Kind() == slog.KindGroup && a.Key != "" {
+			block := logBlock{title: a.Key}
+
+			for _, ga := range a.Value.Group() {
+				h.walkAttr([]string{a.Key}, ga, func(prefix []string, leaf slog.Attr) {
+					block.lines = append(block.lines, formatLogLine(prefix[1:], leaf))
+				})
+			}
+
+			if len(block.lines) > 0 {
+				blocks = append(blocks, block)
+			}
+
+			return true
+		}
+
+		h.walkAttr(nil, a, func(prefix []string, leaf slog.Attr) {
+			if withProps && len(prefix) == 0 && setAnnotationProperty(&annotation, leaf) {
+				return
+			}
+
+			text.WriteString(" ")
+			text.WriteString(strings.Join(append(slices.Clip(prefix), leaf.Key), "."))
+			text.WriteString("=")
+			text.WriteString(formatLogValue(leaf.Value))
+		})
+
+		return true
+	}
+
+	for _, a := range h.attrs {
+		add(a)
+	}
+
+	record.Attrs(add)
+
+	var props []property
+	if withProps {
+		props = annotation.properties()
+	}
+
+	title := strings.Join(h.groups, " / ")
+
+	logGroup.Lock()
+	defer logGroup.Unlock()
+
+	switchLogGroup(title)
+	issueCommand(name, props, text.String())
+
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	for _, block := range blocks {
+		switchLogGroup(strings.Join(append(slices.Clip(h.groups), block.title), " / "))
+
+		for _, line := range block.lines {
+			writeCommandOutput(line + "\n")
+		}
+	}
+
+	// back to the group of the logger.
+	switchLogGroup(title)
+
+	return nil
+}
+
+// walkAttr calls fn for each attribute of a (the attributes of the groups, with the keys of the groups as prefix).
+func (h *LogHandler) walkAttr(prefix []string, a slog.Attr, fn func(prefix []string, a slog.Attr)) {
+	if h.opts.ReplaceAttr != nil && a.Value.Kind() != slog.KindGroup {
+		a = h.opts.ReplaceAttr(prefix, a)
+	}
+
+	a.Value = a.Value.Resolve()
+
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix = append(slices.Clip(prefix), a.Key)
+		}
+
+		for _, ga := range a.Value.Group() {
+			h.walkAttr(prefix, ga, fn)
+		}
+
+		return
+	}
+
+	fn(prefix, a)
+}
+
+// formatLogLine formats an attribute of a group of the log (key=value),
+// the values on several lines are quoted: a line cannot start a workflow command.
+func formatLogLine(prefix []string, a slog.Attr) string {
+	value := formatLogValue(a.Value)
+	if strings.ContainsAny(value, "\r\n") {
+		value = strconv.Quote(a.Value.String())
+	}
+
+	return strings.Join(append(slices.Clip(prefix), a.Key), ".") + "=" + value
+}
+
+// setAnnotationProperty defines a property of the annotation from an attribute.
+func setAnnotationProperty(annotation *Annotation, a slog.Attr) bool {
+	switch a.Key {
+	case "title":
+		annotation.Title = a.Value.String()
+	case "file":
+		annotation.File = a.Value.String()
+	case "line":
+		return setIntProperty(&annotation.Line, a.Value)
+	case "endLine":
+		return setIntProperty(&annotation.EndLine, a.Value)
+	case "col":
+		return setIntProperty(&annotation.Col, a.Value)
+	case "endColumn":
+		return setIntProperty(&annotation.EndColumn, a.Value)
+	default:
+		return false
+	}
+
+	return true
+}
+
+func setIntProperty(dst *int, value slog.Value) bool {
+	switch value.Kind() {
+	case slog.KindInt64:
+		*dst = int(value.Int64())
+	case slog.KindUint64:
+		*dst = int(value.Uint64()) //nolint:gosec // line and column numbers.
+	default:
+		n, err := strconv.Atoi(value.String())
+		if err != nil {
+			return false
+		}
+
+		*dst = n
+	}
+
+	return true
+}
+
+// formatLogValue formats a value, the strings with spaces are quoted.
+func formatLogValue(value slog.Value) string {
+	s := value.String()
+
+	if s == "" || strings.ContainsAny(s, " \t\"=") {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
+
+// logCommand the workflow command of a level.
+func logCommand(level slog.Level) string {
+	switch {
+	case level < slog.LevelInfo:
+		return "debug"
+	case level < slog.LevelWarn:
+		return string(AnnotationNotice)
+	case level < slog.LevelError:
+		return string(AnnotationWarning)
+	default:
+		return string(AnnotationError)
+	}
+}
+
+// switchLogGroup closes the open group of the log, and opens the group title (no group when empty).
+// The lock of logGroup is held.
+func switchLogGroup(title string) {
+	if logGroup.title == title {
+		return
+	}
+
+	if logGroup.title != "" {
+		issueCommand("endgroup", nil, "")
+	}
+
+	if title != "" {
+		issueCommand("group", nil, title)
+	}
+
+	logGroup.title = title
+}
+
+// closeLogGroup closes the open group of the log.
+func closeLogGroup() {
+	logGroup.Lock()
+	defer logGroup.Unlock()
+
+	switchLogGroup("")
+}
+
+// defaultLogger returns the logger of the actions without Logger:
+// a LogHandler inside GitHub Actions (GITHUB_ACTIONS=true), a text handler on stderr otherwise (e.g. a webhook server).
+func defaultLogger() *slog.Logger {
+	if os.Getenv(GithubActions) == "true" {
+		return slog.New(NewLogHandler(nil))
+	}
+
+	return slog.New(slog.NewTextHandler(os.Stderr, nil))
+}
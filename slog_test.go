@@ -0,0 +1,185 @@
+package ghactions
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/google/go-github/v71/github"
+)
+
+// discardLogger a logger without output, for the tests of the workflow commands.
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestLogHandler(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		opts     *slog.HandlerOptions
+		log      func(logger *slog.Logger)
+		expected string
+	}{
+		{
+			desc: "levels",
+			log: func(logger *slog.Logger) {
+				logger.Debug("debug")
+				logger.Info("info")
+				logger.Warn("warn")
+				logger.Error("error")
+			},
+			expected: "::debug::debug\n::notice::info\n::warning::warn\n::error::error\n",
+		},
+		{
+			desc: "minimum level",
+			opts: &slog.HandlerOptions{Level: slog.LevelWarn},
+			log: func(logger *slog.Logger) {
+				logger.Debug("debug")
+				logger.Info("info")
+				logger.Warn("warn")
+			},
+			expected: "::warning::warn\n",
+		},
+		{
+			desc: "annotation properties",
+			log: func(logger *slog.Logger) {
+				logger.Error("invalid config", "file", "a.yml", "line", 3, "col", "7", "title", "Config", "key", "on push")
+			},
+			expected: "::error title=Config,file=a.yml,line=3,col=7::invalid config key=\"on push\"\n",
+		},
+		{
+			desc: "debug without properties",
+			log: func(logger *slog.Logger) {
+				logger.Debug("message", "file", "a.go", "line", 3)
+			},
+			expected: "::debug::message file=a.go line=3\n",
+		},
+		{
+			desc: "attributes",
+			log: func(logger *slog.Logger) {
+				logger.With("event", "push").Warn("failed", "error", errors.New("boom: 100%"), slog.Group("req", "id", 42, "path", "/x"))
+			},
+			expected: "::warning::failed event=push error=\"boom: 100%25\"\n::group::req\nid=42\npath=/x\n::endgroup::\n",
+		},
+		{
+			desc: "group attributes",
+			log: func(logger *slog.Logger) {
+				logger.WithGroup("deploy").Info("done",
+					slog.Group("target", "env", "prod", slog.Group("region", "name", "eu")),
+					slog.Group("output", "log", "line 1\n::error::line 2"),
+					slog.Group("empty"))
+				logger.Debug("debug", slog.Group("req", "id", 42))
+			},
+			expected: "::group::deploy\n::notice::done\n" +
+				"::endgroup::\n::group::deploy / target\nenv=prod\nregion.name=eu\n" +
+				"::endgroup::\n::group::deploy / output\nlog=\"line 1\\n::error::line 2\"\n" +
+				"::endgroup::\n::group::deploy\n" +
+				"::endgroup::\n::debug::debug req.id=42\n",
+		},
+		{
+			desc: "replace attributes",
+			opts: &slog.HandlerOptions{ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+				if a.Key == "token" {
+					return slog.String("token", "***")
+				}
+
+				return a
+			}},
+			log: func(logger *slog.Logger) {
+				logger.Info("login", "token", "secret")
+			},
+			expected: "::notice::login token=***\n",
+		},
+		{
+			desc: "groups",
+			log: func(logger *slog.Logger) {
+				build := logger.WithGroup("build")
+
+				build.Info("compile")
+				build.Info("link")
+				build.WithGroup("tests").Warn("flaky", "test", "TestA")
+				logger.Info("done")
+				build.Info("again")
+			},
+			expected: "::group::build\n::notice::compile\n::notice::link\n" +
+				"::endgroup::\n::group::build / tests\n::warning::flaky test=TestA\n" +
+				"::endgroup::\n::notice::done\n" +
+				"::group::build\n::notice::again\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			buf := captureCommands(t)
+			t.Cleanup(closeLogGroup)
+
+			test.log(slog.New(NewLogHandler(test.opts)))
+
+			if buf.String() != test.expected {
+				t.Errorf("got %q, want %q", buf.String(), test.expected)
+			}
+		})
+	}
+}
+
+func TestLogHandler_StartGroup(t *testing.T) {
+	buf := captureCommands(t)
+
+	logger := slog.New(NewLogHandler(nil))
+
+	logger.WithGroup("setup").Info("install")
+	StartGroup("output")
+	EndGroup()
+	logger.WithGroup("setup").Info("configure")
+	EndGroup()
+	logger.Info("done")
+
+	expected := "::group::setup\n::notice::install\n::endgroup::\n::group::output\n::endgroup::\n" +
+		"::group::setup\n::notice::configure\n::endgroup::\n::notice::done\n"
+
+	if buf.String() != expected {
+		t.Errorf("got %q, want %q", buf.String(), expected)
+	}
+}
+
+func TestAction_Logger(t *testing.T) {
+	buf := captureCommands(t)
+
+	t.Setenv(GithubActions, "true")
+
+	action := NewAction(context.Background()).
+		OnPush(func(*github.Client, *github.PushEvent) error { return nil }, Branches("main"))
+
+	err := action.Handle(context.Background(), "push", []byte(`{"ref":"refs/heads/feature"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = action.Handle(context.Background(), "push", []byte(`{"ref":"refs/heads/main"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "::notice::skip the event event=push reason=\"the branch \\\"feature\\\" does not match the branches filter [main]\"\n" +
+		"::debug::dispatch the event event=push\n"
+
+	if buf.String() != expected {
+		t.Errorf("got %q, want %q", buf.String(), expected)
+	}
+}
+
+func Test_defaultLogger(t *testing.T) {
+	t.Setenv(GithubActions, "true")
+
+	if _, ok := defaultLogger().Handler().(*LogHandler); !ok {
+		t.Errorf("got %T, want a LogHandler inside GitHub Actions", defaultLogger().Handler())
+	}
+
+	t.Setenv(GithubActions, "")
+
+	if _, ok := defaultLogger().Handler().(*slog.TextHandler); !ok {
+		t.Errorf("got %T, want a text handler outside GitHub Actions", defaultLogger().Handler())
+	}
+}
@@ -0,0 +1,220 @@
+package toolcache
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ldez/ghactions"
+)
+
+// maxAttempts the maximum number of attempts of a download.
+const maxAttempts = 3
+
+// retryDelay the delay between the attempts of a download.
+var retryDelay = 10 * time.Second
+
+// DownloadOption An option of Download.
+type DownloadOption func(*downloadConfig)
+
+type downloadConfig struct {
+	client        *http.Client
+	dest          string
+	authorization string
+	headers       map[string]string
+	sha256        string
+}
+
+// WithDestination Defines the path of the downloaded file (a new file inside RUNNER_TEMP by default).
+func WithDestination(path string) DownloadOption {
+	return func(cfg *downloadConfig) {
+		cfg.dest = path
+	}
+}
+
+// WithAuthorization Defines the Authorization header (e.g. "token ghp_xxx").
+func WithAuthorization(authorization string) DownloadOption {
+	return func(cfg *downloadConfig) {
+		cfg.authorization = authorization
+	}
+}
+
+// WithHeader Adds a header to the request.
+func WithHeader(key, value string) DownloadOption {
+	return func(cfg *downloadConfig) {
+		cfg.headers[key] = value
+	}
+}
+
+// WithSHA256 Verifies the SHA-256 checksum (hexadecimal) of the downloaded file.
+func WithSHA256(checksum string) DownloadOption {
+	return func(cfg *downloadConfig) {
+		cfg.sha256 = checksum
+	}
+}
+
+// WithHTTPClient Defines the HTTP client (http.DefaultClient by default).
+func WithHTTPClient(client *http.Client) DownloadOption {
+	return func(cfg *downloadConfig) {
+		cfg.client = client
+	}
+}
+
+// httpError an unexpected HTTP status.
+type httpError struct {
+	url        string
+	statusCode int
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("download %s: unexpected status code %d", e.url, e.statusCode)
+}
+
+// retryable the server errors, the timeouts, and the rate limits are retried (like the toolkit).
+func (e *httpError) retryable() bool {
+	return e.statusCode >= http.StatusInternalServerError ||
+		e.statusCode == http.StatusRequestTimeout ||
+		e.statusCode == http.StatusTooManyRequests
+}
+
+// Download Downloads a file, and returns its path.
+// The requests failing with a server error are retried.
+func Download(ctx context.Context, url string, opts ...DownloadOption) (string, error) {
+	cfg := &downloadConfig{client: http.DefaultClient, headers: map[string]string{}}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if cfg.dest == "" {
+		var err error
+
+		cfg.dest, err = tempPath()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err := os.MkdirAll(filepath.Dir(cfg.dest), 0o755)
+	if err != nil {
+		return "", err
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = download(ctx, cfg, url)
+
+		var herr *httpError
+		if err == nil || attempt == maxAttempts || !errors.As(err, &herr) || !herr.retryable() {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(retryDelay):
+		}
+	}
+
+	if err != nil {
+		_ = os.Remove(cfg.dest)
+		return "", err
+	}
+
+	if cfg.sha256 != "" {
+		err = VerifySHA256(cfg.dest, cfg.sha256)
+		if err != nil {
+			_ = os.Remove(cfg.dest)
+			return "", err
+		}
+	}
+
+	return cfg.dest, nil
+}
+
+func download(ctx context.Context, cfg *downloadConfig, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	if cfg.authorization != "" {
+		req.Header.Set("Authorization", cfg.authorization)
+	}
+
+	for key, value := range cfg.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := cfg.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("download %s: %w", url, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return &httpError{url: url, statusCode: resp.StatusCode}
+	}
+
+	file, err := os.Create(cfg.dest)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(file, resp.Body)
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("download %s: %w", url, err)
+	}
+
+	return file.Close()
+}
+
+// VerifySHA256 Verifies the SHA-256 checksum (hexadecimal) of a file.
+func VerifySHA256(path, checksum string) error {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	hash := sha256.New()
+
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return err
+	}
+
+	actual := hex.EncodeToString(hash.Sum(nil))
+	if !strings.EqualFold(actual, strings.TrimSpace(checksum)) {
+		return fmt.Errorf("checksum mismatch of %s: got %s, want %s", filepath.Base(path), actual, checksum)
+	}
+
+	return nil
+}
+
+// tempPath returns a new path inside RUNNER_TEMP (or the temporary directory of the system).
+func tempPath() (string, error) {
+	dir := os.Getenv(ghactions.RunnerTemp)
+	if dir == "" {
+		dir = os.TempDir()
+	}
+
+	b := make([]byte, 16)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, hex.EncodeToString(b)), nil
+}
@@ -0,0 +1,88 @@
+package toolcache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ldez/ghactions"
+)
+
+func TestDownload(t *testing.T) {
+	retryDelay = 0
+
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if attempts.Add(1) == 1 {
+			rw.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		if req.Header.Get("Authorization") != "token secret" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = rw.Write([]byte("hello"))
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv(ghactions.RunnerTemp, t.TempDir())
+
+	// sha256 of "hello".
+	checksum := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+	path, err := Download(context.Background(), server.URL, WithAuthorization("token secret"), WithSHA256(checksum))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if filepath.Dir(path) != os.Getenv(ghactions.RunnerTemp) {
+		t.Errorf("got %s, want a file inside RUNNER_TEMP", path)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "hello" || attempts.Load() != 2 {
+		t.Errorf("got %q after %d attempts", content, attempts.Load())
+	}
+}
+
+func TestDownload_errors(t *testing.T) {
+	retryDelay = 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/missing" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = rw.Write([]byte("hello"))
+	}))
+	t.Cleanup(server.Close)
+
+	dest := filepath.Join(t.TempDir(), "file")
+
+	_, err := Download(context.Background(), server.URL+"/missing", WithDestination(dest))
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("got %v, want a 404 error", err)
+	}
+
+	_, err = Download(context.Background(), server.URL, WithDestination(dest), WithSHA256("0000"))
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("got %v, want a checksum error", err)
+	}
+
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Error("the invalid file must be removed")
+	}
+}
@@ -0,0 +1,208 @@
+package toolcache
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ldez/ghactions/internal/safepath"
+	"github.com/ulikunitz/xz"
+)
+
+// ExtractTarGz Extracts a .tar.gz archive, and returns the destination directory.
+// The destination is a new directory inside RUNNER_TEMP when dest is empty.
+func ExtractTarGz(file, dest string) (string, error) {
+	dest, err := prepareDest(dest)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Open(filepath.Clean(file))
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return "", fmt.Errorf("extract %s: %w", file, err)
+	}
+
+	err = extractTar(tar.NewReader(gz), dest)
+	if err != nil {
+		return "", fmt.Errorf("extract %s: %w", file, err)
+	}
+
+	return dest, nil
+}
+
+// ExtractTarXz Extracts a .tar.xz archive, and returns the destination directory.
+// The destination is a new directory inside RUNNER_TEMP when dest is empty.
+func ExtractTarXz(file, dest string) (string, error) {
+	dest, err := prepareDest(dest)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Open(filepath.Clean(file))
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	xzReader, err := xz.NewReader(bufio.NewReader(f))
+	if err != nil {
+		return "", fmt.Errorf("extract %s: %w", file, err)
+	}
+
+	err = extractTar(tar.NewReader(xzReader), dest)
+	if err != nil {
+		return "", fmt.Errorf("extract %s: %w", file, err)
+	}
+
+	return dest, nil
+}
+
+// ExtractZip Extracts a .zip archive, and returns the destination directory.
+// The destination is a new directory inside RUNNER_TEMP when dest is empty.
+func ExtractZip(file, dest string) (string, error) {
+	dest, err := prepareDest(dest)
+	if err != nil {
+		return "", err
+	}
+
+	reader, err := zip.OpenReader(filepath.Clean(file))
+	if err != nil {
+		return "", fmt.Errorf("extract %s: %w", file, err)
+	}
+
+	defer func() { _ = reader.Close() }()
+
+	for _, f := range reader.File {
+		err = extractZipFile(f, dest)
+		if err != nil {
+			return "", fmt.Errorf("extract %s: %w", file, err)
+		}
+	}
+
+	return dest, nil
+}
+
+func extractZipFile(f *zip.File, dest string) error {
+	target, err := safepath.Target(dest, f.Name)
+	if err != nil {
+		return err
+	}
+
+	if f.FileInfo().IsDir() {
+		return os.MkdirAll(target, 0o755)
+	}
+
+	src, err := f.Open()
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = src.Close() }()
+
+	return writeFile(target, src, f.Mode())
+}
+
+func extractTar(reader *tar.Reader, dest string) error {
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		target, err := safepath.Target(dest, header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0o755)
+
+		case tar.TypeReg:
+			err = writeFile(target, reader, header.FileInfo().Mode())
+
+		case tar.TypeSymlink:
+			// the links outside the destination are refused.
+			err = safepath.Link(dest, header.Name, header.Linkname)
+			if err == nil {
+				err = os.MkdirAll(filepath.Dir(target), 0o755)
+			}
+
+			if err == nil {
+				err = os.Symlink(header.Linkname, target)
+			}
+
+		case tar.TypeLink:
+			var source string
+
+			source, err = safepath.Target(dest, header.Linkname)
+			if err == nil {
+				err = os.Link(source, target)
+			}
+
+		default:
+			// ignores the other types (e.g. the devices).
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func writeFile(target string, src io.Reader, mode os.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(target), 0o755)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode.Perm())
+	if err != nil {
+		return err
+	}
+
+	//nolint:gosec // the archives of the tools are trusted (see WithSHA256).
+	_, err = io.Copy(file, src)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+func prepareDest(dest string) (string, error) {
+	if dest == "" {
+		var err error
+
+		dest, err = tempPath()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	dest, err := filepath.Abs(dest)
+	if err != nil {
+		return "", err
+	}
+
+	return dest, os.MkdirAll(dest, 0o755)
+}
@@ -0,0 +1,248 @@
+package toolcache
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ulikunitz/xz"
+)
+
+func TestExtractTarGz(t *testing.T) {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+
+	writeTarEntry(t, tw, &tar.Header{Name: "tool/", Typeflag: tar.TypeDir, Mode: 0o755})
+	writeTarEntry(t, tw, &tar.Header{Name: "tool/bin/tool", Typeflag: tar.TypeReg, Mode: 0o755, Size: 5}, "hello")
+	writeTarEntry(t, tw, &tar.Header{Name: "tool/link", Typeflag: tar.TypeSymlink, Linkname: "bin/tool"})
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "tool.tar.gz")
+	writeTestFile(t, archive, buf.String())
+
+	dest, err := ExtractTarGz(archive, filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dest, "tool", "link"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "hello" {
+		t.Errorf("got %q", content)
+	}
+
+	info, err := os.Stat(filepath.Join(dest, "tool", "bin", "tool"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Mode().Perm()&0o100 == 0 {
+		t.Errorf("the file must be executable: %v", info.Mode())
+	}
+}
+
+func TestExtractTarGz_unsafe(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		headers []*tar.Header
+	}{
+		{
+			desc:    "path traversal",
+			headers: []*tar.Header{{Name: "../evil", Typeflag: tar.TypeReg, Mode: 0o644}},
+		},
+		{
+			desc:    "absolute link",
+			headers: []*tar.Header{{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"}},
+		},
+		{
+			desc:    "link outside",
+			headers: []*tar.Header{{Name: "dir/link", Typeflag: tar.TypeSymlink, Linkname: "../../evil"}},
+		},
+		{
+			desc: "chained links",
+			headers: []*tar.Header{
+				{Name: "a/l", Typeflag: tar.TypeSymlink, Linkname: ".."},
+				{Name: "a/l/l2", Typeflag: tar.TypeSymlink, Linkname: ".."},
+				{Name: "a/l/l2/x", Typeflag: tar.TypeReg, Mode: 0o644},
+			},
+		},
+		{
+			desc: "file through a link",
+			headers: []*tar.Header{
+				{Name: "l", Typeflag: tar.TypeSymlink, Linkname: "."},
+				{Name: "l/x", Typeflag: tar.TypeReg, Mode: 0o644},
+			},
+		},
+		{
+			desc: "file replacing a link",
+			headers: []*tar.Header{
+				{Name: "l", Typeflag: tar.TypeSymlink, Linkname: "x"},
+				{Name: "l", Typeflag: tar.TypeReg, Mode: 0o644},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			gz := gzip.NewWriter(buf)
+			tw := tar.NewWriter(gz)
+
+			for _, header := range test.headers {
+				writeTarEntry(t, tw, header)
+			}
+
+			_ = tw.Close()
+			_ = gz.Close()
+
+			archive := filepath.Join(t.TempDir(), "evil.tar.gz")
+			writeTestFile(t, archive, buf.String())
+
+			root := t.TempDir()
+
+			_, err := ExtractTarGz(archive, filepath.Join(root, "out", "dest"))
+			if err == nil || !strings.Contains(err.Error(), "invalid") {
+				t.Errorf("got %v, want an error", err)
+			}
+
+			for _, path := range []string{filepath.Join(root, "x"), filepath.Join(root, "out", "x")} {
+				if _, err := os.Lstat(path); err == nil {
+					t.Errorf("%s: created outside the destination", path)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractZip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+
+	w, err := zw.Create("tool/readme.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = w.Write([]byte("readme"))
+
+	if err = zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "tool.zip")
+	writeTestFile(t, archive, buf.String())
+
+	t.Setenv("RUNNER_TEMP", t.TempDir())
+
+	dest, err := ExtractZip(archive, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dest, "tool", "readme.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "readme" {
+		t.Errorf("got %q", content)
+	}
+}
+
+func TestExtractTarXz(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		header  *tar.Header
+		invalid bool
+	}{
+		{
+			desc:   "file",
+			header: &tar.Header{Name: "tool/file.txt", Typeflag: tar.TypeReg, Mode: 0o644, Size: 2},
+		},
+		{
+			desc:    "path traversal",
+			header:  &tar.Header{Name: "../evil", Typeflag: tar.TypeReg, Mode: 0o644, Size: 2},
+			invalid: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+
+			xw, err := xz.NewWriter(buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tw := tar.NewWriter(xw)
+
+			writeTarEntry(t, tw, test.header, "xz")
+
+			if err = tw.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if err = xw.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			archive := filepath.Join(t.TempDir(), "tool.tar.xz")
+			writeTestFile(t, archive, buf.String())
+
+			dest, err := ExtractTarXz(archive, filepath.Join(t.TempDir(), "out"))
+			if test.invalid {
+				if err == nil {
+					t.Error("expected an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			content, err := os.ReadFile(filepath.Join(dest, test.header.Name))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(content) != "xz" {
+				t.Errorf("got %q", content)
+			}
+		})
+	}
+}
+
+func writeTarEntry(t *testing.T, tw *tar.Writer, header *tar.Header, content ...string) {
+	t.Helper()
+
+	err := tw.WriteHeader(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range content {
+		_, err = tw.Write([]byte(c))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
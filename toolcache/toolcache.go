@@ -0,0 +1,330 @@
+// Package toolcache Manages the tools of the runner tool cache: $RUNNER_TOOL_CACHE/<tool>/<version>/<arch>.
+//
+// The layout (including the <arch>.complete marker files) is the layout of the official toolkit (@actions/tool-cache),
+// the caches are shared with the other actions (e.g. actions/setup-go).
+package toolcache
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ldez/ghactions"
+)
+
+// ErrNotFound The tool is not in the cache.
+var ErrNotFound = errors.New("tool not found in the cache")
+
+// Arch Returns the name of the architecture of the current platform, as named by the runner (x64, arm64, etc.).
+func Arch() string {
+	switch runtime.GOARCH {
+	case "amd64":
+		return "x64"
+	case "386":
+		return "ia32"
+	default:
+		return runtime.GOARCH
+	}
+}
+
+// Find Finds a tool in the cache, and returns its directory.
+// The version can be an exact version (1.22.3) or a pattern (1.22.x, 1.x, *): the highest matching version is used.
+// The arch is the current architecture when empty.
+func Find(tool, version, arch string) (string, error) {
+	root, err := cacheRoot()
+	if err != nil {
+		return "", err
+	}
+
+	arch = cmp.Or(arch, Arch())
+
+	if isExplicit(version) {
+		dir := filepath.Join(root, tool, cleanVersion(version), arch)
+		if !isComplete(dir) {
+			return "", fmt.Errorf("%s %s (%s): %w", tool, version, arch, ErrNotFound)
+		}
+
+		return dir, nil
+	}
+
+	versions, err := FindAll(tool, arch)
+	if err != nil {
+		return "", err
+	}
+
+	// the versions are sorted.
+	for _, v := range slices.Backward(versions) {
+		if matchVersion(version, v) {
+			return filepath.Join(root, tool, v, arch), nil
+		}
+	}
+
+	return "", fmt.Errorf("%s %s (%s): %w", tool, version, arch, ErrNotFound)
+}
+
+// FindAll Returns the cached versions of a tool (sorted by semantic version).
+// The arch is the current architecture when empty.
+func FindAll(tool, arch string) ([]string, error) {
+	root, err := cacheRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	arch = cmp.Or(arch, Arch())
+
+	entries, err := os.ReadDir(filepath.Join(root, tool))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+
+	for _, entry := range entries {
+		if entry.IsDir() && isComplete(filepath.Join(root, tool, entry.Name(), arch)) {
+			versions = append(versions, entry.Name())
+		}
+	}
+
+	slices.SortFunc(versions, compareVersions)
+
+	return versions, nil
+}
+
+// CacheDir Copies a directory (e.g. an extracted archive) into the cache, and returns the directory of the cache.
+// The arch is the current architecture when empty.
+func CacheDir(source, tool, version, arch string) (string, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return "", err
+	}
+
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", source)
+	}
+
+	dest, err := createCacheDir(tool, version, arch)
+	if err != nil {
+		return "", err
+	}
+
+	err = copyDir(source, dest)
+	if err != nil {
+		return "", fmt.Errorf("copy %s to the cache: %w", source, err)
+	}
+
+	return dest, markComplete(dest)
+}
+
+// CacheFile Copies a file (e.g. a binary) into the cache with the name target, and returns the directory of the cache.
+// The arch is the current architecture when empty.
+func CacheFile(source, target, tool, version, arch string) (string, error) {
+	dest, err := createCacheDir(tool, version, arch)
+	if err != nil {
+		return "", err
+	}
+
+	err = copyFile(source, filepath.Join(dest, filepath.Base(target)))
+	if err != nil {
+		return "", fmt.Errorf("copy %s to the cache: %w", source, err)
+	}
+
+	return dest, markComplete(dest)
+}
+
+// createCacheDir creates an empty cache directory, the previous content is removed.
+func createCacheDir(tool, version, arch string) (string, error) {
+	root, err := cacheRoot()
+	if err != nil {
+		return "", err
+	}
+
+	dest := filepath.Join(root, tool, cleanVersion(version), cmp.Or(arch, Arch()))
+
+	for _, path := range []string{dest, dest + ".complete"} {
+		err = os.RemoveAll(path)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = os.MkdirAll(dest, 0o755)
+	if err != nil {
+		return "", err
+	}
+
+	return dest, nil
+}
+
+// markComplete writes the marker file of a complete cache: <version>/<arch>.complete.
+func markComplete(dir string) error {
+	return os.WriteFile(dir+".complete", nil, 0o600)
+}
+
+func isComplete(dir string) bool {
+	_, err := os.Stat(dir + ".complete")
+	return err == nil
+}
+
+func cacheRoot() (string, error) {
+	root := os.Getenv(ghactions.RunnerToolCache)
+	if root == "" {
+		return "", fmt.Errorf("%s is not defined", ghactions.RunnerToolCache)
+	}
+
+	return root, nil
+}
+
+// cleanVersion removes the "v" prefix of a semantic version, like the toolkit.
+func cleanVersion(version string) string {
+	v, err := ghactions.ParseVersion(strings.TrimPrefix(strings.TrimSpace(version), "="))
+	if err != nil {
+		return version
+	}
+
+	return v.String()
+}
+
+func isExplicit(version string) bool {
+	_, err := ghactions.ParseVersion(strings.TrimPrefix(strings.TrimSpace(version), "="))
+	return err == nil
+}
+
+// matchVersion checks if a version matches a pattern (1.22.x, 1.x, 1, *).
+// The pre-releases are only matched by an explicit version.
+func matchVersion(pattern, version string) bool {
+	v, err := ghactions.ParseVersion(version)
+	if err != nil || v.IsPrerelease() {
+		return false
+	}
+
+	pattern = strings.TrimPrefix(strings.TrimSpace(pattern), "v")
+	if pattern == "" || pattern == "*" {
+		return true
+	}
+
+	parts := strings.Split(pattern, ".")
+	if len(parts) > 3 {
+		return false
+	}
+
+	for i, value := range []int{v.Major, v.Minor, v.Patch}[:len(parts)] {
+		switch parts[i] {
+		case "x", "X", "*":
+			continue
+		default:
+			n, err := strconv.Atoi(parts[i])
+			if err != nil || n != value {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// compareVersions compares semantic versions, the invalid versions are sorted first.
+func compareVersions(a, b string) int {
+	va, errA := ghactions.ParseVersion(a)
+	vb, errB := ghactions.ParseVersion(b)
+
+	switch {
+	case errA != nil && errB != nil:
+		return strings.Compare(a, b)
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	}
+
+	return cmp.Or(
+		cmp.Compare(va.Major, vb.Major),
+		cmp.Compare(va.Minor, vb.Minor),
+		cmp.Compare(va.Patch, vb.Patch),
+		comparePrerelease(va.Prerelease, vb.Prerelease),
+	)
+}
+
+// comparePrerelease a version without pre-release is higher.
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func copyFile(source, dest string) error {
+	src, err := os.Open(filepath.Clean(source))
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = src.Close() }()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(filepath.Clean(dest), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(dst, src)
+	if err != nil {
+		_ = dst.Close()
+		return err
+	}
+
+	return dst.Close()
+}
+
+// copyDir copies a directory, the symbolic links are preserved.
+func copyDir(source, dest string) error {
+	return filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dest, rel)
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, 0o755)
+
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+
+			return os.Symlink(link, target)
+
+		default:
+			return copyFile(path, target)
+		}
+	})
+}
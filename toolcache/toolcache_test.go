@@ -0,0 +1,161 @@
+package toolcache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ldez/ghactions"
+)
+
+func setupCache(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	t.Setenv(ghactions.RunnerToolCache, root)
+
+	return root
+}
+
+func TestCacheDir(t *testing.T) {
+	root := setupCache(t)
+
+	source := t.TempDir()
+	writeTestFile(t, filepath.Join(source, "bin", "tool"), "#!/bin/sh\n")
+
+	err := os.Symlink("bin/tool", filepath.Join(source, "tool"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := CacheDir(source, "mytool", "v1.2.3", "x64")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dir != filepath.Join(root, "mytool", "1.2.3", "x64") {
+		t.Errorf("got %s", dir)
+	}
+
+	for _, path := range []string{
+		filepath.Join(root, "mytool", "1.2.3", "x64.complete"),
+		filepath.Join(dir, "bin", "tool"),
+		filepath.Join(dir, "tool"),
+	} {
+		if _, err := os.Stat(path); err != nil {
+			t.Error(err)
+		}
+	}
+
+	found, err := Find("mytool", "1.2.3", "x64")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found != dir {
+		t.Errorf("got %s, want %s", found, dir)
+	}
+}
+
+func TestCacheFile(t *testing.T) {
+	root := setupCache(t)
+
+	source := filepath.Join(t.TempDir(), "download")
+	writeTestFile(t, source, "binary")
+
+	dir, err := CacheFile(source, "tool", "mytool", "2.0.0", "arm64")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(root, "mytool", "2.0.0", "arm64", "tool"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "binary" || dir != filepath.Join(root, "mytool", "2.0.0", "arm64") {
+		t.Errorf("got %s: %q", dir, content)
+	}
+}
+
+func TestFind(t *testing.T) {
+	root := setupCache(t)
+
+	for _, version := range []string{"1.20.5", "1.21.0", "1.21.4", "1.22.0-rc.1", "2.0.0"} {
+		err := os.MkdirAll(filepath.Join(root, "go", version, "x64"), 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		writeTestFile(t, filepath.Join(root, "go", version, "x64.complete"), "")
+	}
+
+	// incomplete.
+	err := os.MkdirAll(filepath.Join(root, "go", "1.21.9", "x64"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	versions, err := FindAll("go", "x64")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedVersions := []string{"1.20.5", "1.21.0", "1.21.4", "1.22.0-rc.1", "2.0.0"}
+	if !slices.Equal(versions, expectedVersions) {
+		t.Errorf("got %v, want %v", versions, expectedVersions)
+	}
+
+	testCases := []struct {
+		version  string
+		expected string
+	}{
+		{version: "1.21.x", expected: "1.21.4"},
+		{version: "1.x", expected: "1.21.4"},
+		{version: "1", expected: "1.21.4"},
+		{version: "*", expected: "2.0.0"},
+		{version: "v1.20.5", expected: "1.20.5"},
+		{version: "1.22.0-rc.1", expected: "1.22.0-rc.1"},
+		{version: "=1.22.0-rc.1", expected: "1.22.0-rc.1"},
+		{version: "1.21.9"},
+		{version: "3.x"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.version, func(t *testing.T) {
+			dir, err := Find("go", test.version, "x64")
+
+			if test.expected == "" {
+				if !errors.Is(err, ErrNotFound) {
+					t.Errorf("got %v, want %v", err, ErrNotFound)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if dir != filepath.Join(root, "go", test.expected, "x64") {
+				t.Errorf("got %s, want %s", dir, test.expected)
+			}
+		})
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(path, []byte(content), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,173 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrQueueFull is returned when a delivery cannot be queued because all the slots are used.
+var ErrQueueFull = errors.New("webhook queue is full")
+
+// ErrQueueClosed is returned when a delivery is queued after the shutdown of the queue.
+var ErrQueueClosed = errors.New("webhook queue is closed")
+
+// Delivery A webhook delivery.
+type Delivery struct {
+	ID        string
+	EventName string
+	Payload   []byte
+}
+
+// HandleFunc Handles a delivery.
+type HandleFunc func(ctx context.Context, delivery Delivery) error
+
+// Queue An asynchronous worker pool.
+type Queue struct {
+	handle  HandleFunc
+	timeout time.Duration
+	onError func(Delivery, error)
+
+	jobs chan Delivery
+	wg   sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+
+	ctx    context.Context //nolint:containedctx // cancelled when the shutdown deadline is reached.
+	cancel context.CancelFunc
+}
+
+// NewQueue Creates a queue and starts the workers.
+//   - workers: the maximum number of deliveries handled concurrently (at least 1).
+//   - size: the maximum number of pending deliveries.
+//   - timeout: the deadline of the context of a handler (no timeout if 0), the worker waits for the handler to return.
+//   - onError: called when a handler fails (can be nil).
+func NewQueue(handle HandleFunc, workers, size int, timeout time.Duration, onError func(Delivery, error)) *Queue {
+	if workers < 1 {
+		workers = 1
+	}
+
+	if size < 0 {
+		size = 0
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	q := &Queue{
+		handle:  handle,
+		timeout: timeout,
+		onError: onError,
+		jobs:    make(chan Delivery, size),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+
+	q.wg.Add(workers)
+
+	for range workers {
+		go q.work()
+	}
+
+	return q
+}
+
+// Enqueue adds a delivery to the queue without blocking.
+func (q *Queue) Enqueue(delivery Delivery) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	select {
+	case q.jobs <- delivery:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Shutdown stops accepting deliveries and waits for the pending ones to be handled.
+// When ctx is done before, the running handlers are cancelled and the context error is returned.
+func (q *Queue) Shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+
+	for delivery := range q.jobs {
+		if q.ctx.Err() != nil {
+			q.fail(delivery, q.ctx.Err())
+			continue
+		}
+
+		err := q.run(delivery)
+		if err != nil {
+			q.fail(delivery, err)
+		}
+	}
+}
+
+// run handles a delivery: the worker is used until the handler returns, even after the timeout.
+// The timeout is the deadline of the context of the handler, the handler must stop when the context is done.
+func (q *Queue) run(delivery Delivery) (err error) {
+	ctx := q.ctx
+
+	if q.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, q.timeout)
+		defer cancel()
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("delivery %s (%s): panic: %v", delivery.ID, delivery.EventName, r)
+		}
+	}()
+
+	err = q.handle(ctx, delivery)
+
+	// the handler has returned: the timeout is reported even if the handler ignored it.
+	if ctxErr := ctx.Err(); ctxErr != nil && !errors.Is(err, ctxErr) {
+		err = errors.Join(err, ctxErr)
+	}
+
+	if err != nil {
+		return fmt.Errorf("delivery %s (%s): %w", delivery.ID, delivery.EventName, err)
+	}
+
+	return nil
+}
+
+func (q *Queue) fail(delivery Delivery, err error) {
+	if q.onError != nil {
+		q.onError(delivery, err)
+	}
+}
@@ -0,0 +1,117 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestQueue_Shutdown(t *testing.T) {
+	var handled atomic.Int32
+
+	handle := func(_ context.Context, _ Delivery) error {
+		time.Sleep(10 * time.Millisecond)
+		handled.Add(1)
+		return nil
+	}
+
+	queue := NewQueue(handle, 2, 10, 0, nil)
+
+	for range 5 {
+		err := queue.Enqueue(Delivery{ID: "id", EventName: "issues"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := queue.Shutdown(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if handled.Load() != 5 {
+		t.Errorf("got %d handled deliveries, want 5", handled.Load())
+	}
+
+	err = queue.Enqueue(Delivery{})
+	if !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("got %v, want %v", err, ErrQueueClosed)
+	}
+}
+
+func TestQueue_timeout(t *testing.T) {
+	errs := make(chan error, 1)
+
+	handle := func(ctx context.Context, _ Delivery) error {
+		<-ctx.Done()
+		return nil
+	}
+
+	queue := NewQueue(handle, 1, 1, 10*time.Millisecond, func(_ Delivery, err error) { errs <- err })
+
+	err := queue.Enqueue(Delivery{ID: "id", EventName: "issues"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err = <-errs:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("got %v, want %v", err, context.DeadlineExceeded)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout not reported")
+	}
+
+	_ = queue.Shutdown(context.Background())
+}
+
+func TestQueue_timeout_workerUsed(t *testing.T) {
+	var running, maxRunning atomic.Int32
+
+	handle := func(_ context.Context, _ Delivery) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+
+		if n > maxRunning.Load() {
+			maxRunning.Store(n)
+		}
+
+		// ignores the context.
+		time.Sleep(50 * time.Millisecond)
+
+		return nil
+	}
+
+	var failures atomic.Int32
+
+	queue := NewQueue(handle, 1, 2, 5*time.Millisecond, func(_ Delivery, err error) {
+		if running.Load() != 0 {
+			t.Error("the timeout is reported while the handler is running")
+		}
+
+		failures.Add(1)
+	})
+
+	for _, id := range []string{"1", "2"} {
+		err := queue.Enqueue(Delivery{ID: id, EventName: "issues"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := queue.Shutdown(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if maxRunning.Load() != 1 {
+		t.Errorf("got %d handlers running concurrently, want 1", maxRunning.Load())
+	}
+
+	if failures.Load() != 2 {
+		t.Errorf("got %d failures, want 2", failures.Load())
+	}
+}
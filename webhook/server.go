@@ -0,0 +1,129 @@
+// Package webhook Serves the GitHub Action handlers over HTTP, as a webhook endpoint.
+//
+// The deliveries are acknowledged immediately (GitHub expects a response within 10 seconds),
+// de-duplicated by their X-GitHub-Delivery ID, and handled asynchronously by a worker pool.
+package webhook
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/ldez/ghactions"
+)
+
+// Config Server configuration.
+type Config struct {
+	// Secret the webhook secret, used to validate the payload signatures (no validation when empty).
+	Secret []byte
+	// Store the store of the delivery IDs (a MemoryStore of DefaultStoreSize when nil).
+	Store Store
+	// Workers the maximum number of deliveries handled concurrently (1 when lower than 1).
+	Workers int
+	// QueueSize the maximum number of pending deliveries.
+	QueueSize int
+	// HandlerTimeout the deadline of a delivery (no timeout when 0):
+	// the requests of the client given to the handler are canceled when the deadline is reached.
+	// The worker is used until the handler returns: the handlers must stop when a request fails.
+	HandlerTimeout time.Duration
+	// OnError called when a delivery fails (logged when nil).
+	OnError func(Delivery, error)
+}
+
+// Server A webhook HTTP handler backed by an Action.
+type Server struct {
+	secret []byte
+	store  Store
+	queue  *Queue
+}
+
+// NewServer Creates a new webhook server and starts its workers.
+func NewServer(action *ghactions.Action, cfg Config) *Server {
+	store := cfg.Store
+	if store == nil {
+		store = NewMemoryStore(DefaultStoreSize)
+	}
+
+	onError := cfg.OnError
+	if onError == nil {
+		onError = func(delivery Delivery, err error) {
+			log.Printf("webhook: delivery %s (%s): %v", delivery.ID, delivery.EventName, err)
+		}
+	}
+
+	handle := func(ctx context.Context, delivery Delivery) error {
+		return action.Handle(ctx, delivery.EventName, delivery.Payload)
+	}
+
+	return &Server{
+		secret: cfg.Secret,
+		store:  store,
+		queue:  NewQueue(handle, cfg.Workers, cfg.QueueSize, cfg.HandlerTimeout, onError),
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	payload, err := github.ValidatePayload(req, s.secret)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	delivery := Delivery{
+		ID:        github.DeliveryID(req),
+		EventName: github.WebHookType(req),
+		Payload:   payload,
+	}
+
+	if delivery.EventName == "" {
+		http.Error(rw, "missing X-GitHub-Event header", http.StatusBadRequest)
+		return
+	}
+
+	if delivery.ID != "" {
+		added, errAdd := s.store.Add(delivery.ID)
+		if errAdd != nil {
+			http.Error(rw, errAdd.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if !added {
+			rw.WriteHeader(http.StatusOK)
+			return
+		}
+	}
+
+	err = s.queue.Enqueue(delivery)
+	if err != nil {
+		if delivery.ID != "" {
+			// allows the redelivery.
+			_ = s.store.Remove(delivery.ID)
+		}
+
+		status := http.StatusServiceUnavailable
+		if !errors.Is(err, ErrQueueFull) && !errors.Is(err, ErrQueueClosed) {
+			status = http.StatusInternalServerError
+		}
+
+		http.Error(rw, err.Error(), status)
+
+		return
+	}
+
+	rw.WriteHeader(http.StatusAccepted)
+}
+
+// Shutdown stops accepting deliveries and waits for the pending ones to be handled.
+// It should be called after http.Server.Shutdown.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.queue.Shutdown(ctx)
+}
@@ -0,0 +1,130 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/ldez/ghactions"
+)
+
+func TestServer(t *testing.T) {
+	payload, err := os.ReadFile("../fixtures/issues.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret := []byte("secret")
+
+	var handled atomic.Int32
+
+	action := ghactions.NewAction(context.Background()).
+		OnIssues(func(_ *github.Client, _ *github.IssuesEvent) error {
+			handled.Add(1)
+			return nil
+		})
+
+	server := NewServer(action, Config{Secret: secret, Workers: 2, QueueSize: 10})
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	testCases := []struct {
+		desc      string
+		delivery  string
+		signature string
+		expected  int
+	}{
+		{desc: "first delivery", delivery: "1", signature: signature, expected: http.StatusAccepted},
+		{desc: "redelivery", delivery: "1", signature: signature, expected: http.StatusOK},
+		{desc: "other delivery", delivery: "2", signature: signature, expected: http.StatusAccepted},
+		{desc: "invalid signature", delivery: "3", signature: "sha256=00", expected: http.StatusBadRequest},
+	}
+
+	for _, test := range testCases {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(github.EventTypeHeader, "issues")
+		req.Header.Set(github.DeliveryIDHeader, test.delivery)
+		req.Header.Set(github.SHA256SignatureHeader, test.signature)
+
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+
+		if rec.Code != test.expected {
+			t.Errorf("%s: got status %d, want %d", test.desc, rec.Code, test.expected)
+		}
+	}
+
+	err = server.Shutdown(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if handled.Load() != 2 {
+		t.Errorf("got %d handled deliveries, want 2", handled.Load())
+	}
+}
+
+// TestServer_HandlerTimeout checks that the deadline of a delivery cancels the requests of the client of the handler.
+func TestServer_HandlerTimeout(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		<-req.Context().Done()
+	}))
+	t.Cleanup(api.Close)
+
+	action := ghactions.NewAction(context.Background()).
+		OnIssues(func(client *github.Client, _ *github.IssuesEvent) error {
+			_, _, err := client.Issues.Get(context.Background(), "ldez", "ghactions", 1)
+			return err
+		})
+
+	action.ClientFactory = func(_ context.Context, _ any) (*github.Client, error) {
+		return github.NewClient(nil).WithEnterpriseURLs(api.URL, api.URL)
+	}
+
+	errs := make(chan error, 1)
+
+	server := NewServer(action, Config{
+		QueueSize:      1,
+		HandlerTimeout: 50 * time.Millisecond,
+		OnError:        func(_ Delivery, err error) { errs <- err },
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(`{"action":"opened"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(github.EventTypeHeader, "issues")
+	req.Header.Set(github.DeliveryIDHeader, "1")
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusAccepted)
+	}
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("got %v, want %v", err, context.DeadlineExceeded)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the request of the handler has not been canceled")
+	}
+
+	err := server.Shutdown(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,291 @@
+package webhook
+
+import (
+	"bufio"
+	"container/list"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DefaultStoreSize the default number of delivery IDs kept by a store.
+const DefaultStoreSize = 10000
+
+// Store Records the delivery IDs already received.
+type Store interface {
+	// Add records a delivery ID and reports whether it was not already known.
+	Add(id string) (bool, error)
+	// Remove forgets a delivery ID (e.g. when the delivery has not been queued).
+	Remove(id string) error
+}
+
+// MemoryStore A bounded in-memory Store: the oldest IDs are evicted first.
+type MemoryStore struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	ids   map[string]*list.Element
+}
+
+// NewMemoryStore Creates a new in-memory store.
+// The store keeps at most size IDs, DefaultStoreSize is used when size is lower than 1.
+func NewMemoryStore(size int) *MemoryStore {
+	if size < 1 {
+		size = DefaultStoreSize
+	}
+
+	return &MemoryStore{
+		size:  size,
+		order: list.New(),
+		ids:   make(map[string]*list.Element),
+	}
+}
+
+// Add records a delivery ID and reports whether it was not already known.
+func (s *MemoryStore) Add(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.add(id), nil
+}
+
+// Remove forgets a delivery ID.
+func (s *MemoryStore) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.remove(id)
+
+	return nil
+}
+
+// Len returns the number of IDs in the store.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.order.Len()
+}
+
+func (s *MemoryStore) add(id string) bool {
+	if _, ok := s.ids[id]; ok {
+		return false
+	}
+
+	s.ids[id] = s.order.PushBack(id)
+
+	for s.order.Len() > s.size {
+		oldest := s.order.Front()
+		s.order.Remove(oldest)
+		delete(s.ids, oldest.Value.(string))
+	}
+
+	return true
+}
+
+func (s *MemoryStore) remove(id string) {
+	elt, ok := s.ids[id]
+	if !ok {
+		return
+	}
+
+	s.order.Remove(elt)
+	delete(s.ids, id)
+}
+
+func (s *MemoryStore) list() []string {
+	ids := make([]string, 0, s.order.Len())
+	for elt := s.order.Front(); elt != nil; elt = elt.Next() {
+		ids = append(ids, elt.Value.(string))
+	}
+
+	return ids
+}
+
+// FileStore A bounded Store persisted in a file, to survive restarts.
+// The file contains one delivery ID per line and is compacted when it grows too much.
+type FileStore struct {
+	mem *MemoryStore
+
+	path   string
+	file   *os.File
+	lines  int
+	closed bool
+}
+
+// NewFileStore Creates a store backed by the file at path.
+// The IDs already in the file are loaded.
+func NewFileStore(path string, size int) (*FileStore, error) {
+	s := &FileStore{
+		mem:  NewMemoryStore(size),
+		path: filepath.Clean(path),
+	}
+
+	err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.compact()
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Add records a delivery ID and reports whether it was not already known.
+func (s *FileStore) Add(id string) (bool, error) {
+	if strings.ContainsAny(id, "\r\n") {
+		return false, fmt.Errorf("invalid delivery ID: %q", id)
+	}
+
+	s.mem.mu.Lock()
+	defer s.mem.mu.Unlock()
+
+	if s.closed {
+		return false, errors.New("delivery store closed")
+	}
+
+	if !s.mem.add(id) {
+		return false, nil
+	}
+
+	err := s.open()
+	if err != nil {
+		s.mem.remove(id)
+		return false, err
+	}
+
+	_, err = fmt.Fprintln(s.file, id)
+	if err != nil {
+		s.mem.remove(id)
+		return false, fmt.Errorf("write delivery ID: %w", err)
+	}
+
+	s.lines++
+
+	if s.lines > 2*s.mem.size {
+		// the ID is already written: a failed compaction is retried by the next Add.
+		err = s.compact()
+		if err != nil {
+			log.Printf("webhook: %v", err)
+		}
+	}
+
+	return true, nil
+}
+
+// Remove forgets a delivery ID.
+func (s *FileStore) Remove(id string) error {
+	s.mem.mu.Lock()
+	defer s.mem.mu.Unlock()
+
+	if _, ok := s.mem.ids[id]; !ok {
+		return nil
+	}
+
+	s.mem.remove(id)
+
+	return s.compact()
+}
+
+// Close closes the underlying file.
+func (s *FileStore) Close() error {
+	s.mem.mu.Lock()
+	defer s.mem.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	s.closed = true
+
+	err := s.file.Close()
+	s.file = nil
+
+	return err
+}
+
+func (s *FileStore) load() error {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open delivery store: %w", err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		id := strings.TrimSpace(scanner.Text())
+		if id != "" {
+			s.mem.add(id)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// compact rewrites the file with the IDs currently in memory.
+// The file is reopened even when the rewrite fails (the previous file is kept).
+// The caller must hold the lock (or be the constructor).
+func (s *FileStore) compact() error {
+	if s.file != nil {
+		_ = s.file.Close()
+		s.file = nil
+	}
+
+	ids := s.mem.list()
+
+	err := s.rewrite(ids)
+
+	errOpen := s.open()
+	if err != nil || errOpen != nil {
+		return errors.Join(err, errOpen)
+	}
+
+	s.lines = len(ids)
+
+	return nil
+}
+
+// rewrite replaces the file with a file containing the IDs.
+func (s *FileStore) rewrite(ids []string) error {
+	tmp := s.path + ".tmp"
+
+	err := os.WriteFile(tmp, []byte(strings.Join(append(ids, ""), "\n")), 0o600)
+	if err != nil {
+		return fmt.Errorf("write delivery store: %w", err)
+	}
+
+	err = os.Rename(tmp, s.path)
+	if err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("replace delivery store: %w", err)
+	}
+
+	return nil
+}
+
+// open opens the file in append mode, when it is not open.
+func (s *FileStore) open() error {
+	if s.file != nil {
+		return nil
+	}
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open delivery store: %w", err)
+	}
+
+	s.file = file
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package webhook
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore(2)
+
+	for _, id := range []string{"a", "b", "c"} {
+		added, err := store.Add(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !added {
+			t.Errorf("%s: expected to be added", id)
+		}
+	}
+
+	if store.Len() != 2 {
+		t.Errorf("got %d IDs, want 2", store.Len())
+	}
+
+	added, _ := store.Add("c")
+	if added {
+		t.Error("c: expected to be a duplicate")
+	}
+
+	// "a" has been evicted.
+	added, _ = store.Add("a")
+	if !added {
+		t.Error("a: expected to be added again")
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deliveries")
+
+	store, err := NewFileStore(path, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		_, err = store.Add(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = store.Remove("g")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = store.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = NewFileStore(path, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = store.Close() })
+
+	testCases := []struct {
+		id       string
+		expected bool
+	}{
+		{id: "e", expected: false},
+		{id: "f", expected: false},
+		{id: "g", expected: true},
+		{id: "d", expected: true},
+	}
+
+	for _, test := range testCases {
+		added, err := store.Add(test.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if added != test.expected {
+			t.Errorf("%s: got added=%v, want %v", test.id, added, test.expected)
+		}
+	}
+}
+
+// TestFileStore_compactError checks that a failed compaction does not lose the IDs, and that the store stays usable.
+func TestFileStore_compactError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deliveries")
+
+	store, err := NewFileStore(path, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = store.Close() })
+
+	// the temporary file of the compaction cannot be written.
+	err = os.Mkdir(path+".tmp", 0o700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"a", "b", "c", "d"} {
+		added, err := store.Add(id)
+		if err != nil {
+			t.Fatalf("%s: %v", id, err)
+		}
+
+		if !added {
+			t.Errorf("%s: expected to be added", id)
+		}
+	}
+
+	added, err := store.Add("d")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if added {
+		t.Error("d: expected to be a duplicate")
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "a\nb\nc\nd\n" {
+		t.Errorf("got %q, want all the IDs", content)
+	}
+}
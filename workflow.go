@@ -0,0 +1,167 @@
+package ghactions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v71/github"
+)
+
+// errUnknownEvent the event is not supported.
+var errUnknownEvent = errors.New("unknown event")
+
+// ScheduleEvent Triggered when a workflow runs on a schedule.
+//
+// GitHub API docs: https://docs.github.com/en/actions/writing-workflows/choosing-when-your-workflow-runs/events-that-trigger-workflows#schedule
+type ScheduleEvent struct {
+	// Schedule the cron expression that triggered the workflow.
+	Schedule *string `json:"schedule,omitempty"`
+	// Workflow the path of the workflow file.
+	Workflow *string `json:"workflow,omitempty"`
+
+	Repository   *github.Repository   `json:"repository,omitempty"`
+	Organization *github.Organization `json:"organization,omitempty"`
+	Sender       *github.User         `json:"sender,omitempty"`
+	Installation *github.Installation `json:"installation,omitempty"`
+}
+
+// GetSchedule returns the Schedule field if it's non-nil, zero value otherwise.
+func (e *ScheduleEvent) GetSchedule() string {
+	if e == nil || e.Schedule == nil {
+		return ""
+	}
+	return *e.Schedule
+}
+
+// GetWorkflow returns the Workflow field if it's non-nil, zero value otherwise.
+func (e *ScheduleEvent) GetWorkflow() string {
+	if e == nil || e.Workflow == nil {
+		return ""
+	}
+	return *e.Workflow
+}
+
+// GetRepository returns the Repository field.
+func (e *ScheduleEvent) GetRepository() *github.Repository {
+	if e == nil {
+		return nil
+	}
+	return e.Repository
+}
+
+// GetOrganization returns the Organization field.
+func (e *ScheduleEvent) GetOrganization() *github.Organization {
+	if e == nil {
+		return nil
+	}
+	return e.Organization
+}
+
+// GetSender returns the Sender field.
+func (e *ScheduleEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+// GetInstallation returns the Installation field.
+func (e *ScheduleEvent) GetInstallation() *github.Installation {
+	if e == nil {
+		return nil
+	}
+	return e.Installation
+}
+
+// InputWorkflowCallInputs the input of the action with the inputs of a reusable workflow (`${{ toJSON(inputs) }}`).
+// It is the signal of the reusable-workflow context (see OnWorkflowCall).
+const InputWorkflowCallInputs = "workflow-call-inputs"
+
+// WorkflowCallEvent The context of an action running inside a reusable workflow (workflow_call).
+// The event is the event of the caller (ex: push, merge_group): workflow_call is never the name of the event.
+//
+// GitHub API docs: https://docs.github.com/en/actions/writing-workflows/choosing-when-your-workflow-runs/events-that-trigger-workflows#workflow_call
+type WorkflowCallEvent struct {
+	// EventName the name of the event of the caller (ex: push).
+	EventName string
+	// Event the event of the caller (ex: *github.PushEvent, *github.MergeGroupEvent).
+	Event any
+	// Inputs the inputs of the reusable workflow, with their types (boolean, number, string).
+	Inputs json.RawMessage
+}
+
+// DecodeInputs decodes the inputs of the reusable workflow into v.
+func (e *WorkflowCallEvent) DecodeInputs(v any) error {
+	if e == nil {
+		return nil
+	}
+
+	return DecodeInputs(e.Inputs, v)
+}
+
+// OnWorkflowCall Defines the handler of the action inside a reusable workflow.
+// The handler replaces the handlers of the events when the input workflow-call-inputs is defined:
+//
+//	with:
+//	  workflow-call-inputs: ${{ toJSON(inputs) }}
+func (a *Action) OnWorkflowCall(eventHandler func(*github.Client, *WorkflowCallEvent) error) *Action {
+	a.onWorkflowCall = eventHandler
+	return a
+}
+
+// workflowCallHandler returns the handler of the reusable-workflow context, nil outside this context.
+func (a *Action) workflowCallHandler(eventName string, event any) (func(*github.Client) error, error) {
+	if a.onWorkflowCall == nil {
+		return nil, nil
+	}
+
+	raw := strings.TrimSpace(GetInput(InputWorkflowCallInputs))
+	if raw == "" {
+		return nil, nil
+	}
+
+	if !json.Valid([]byte(raw)) {
+		return nil, fmt.Errorf("invalid input %s: not JSON", InputWorkflowCallInputs)
+	}
+
+	callEvent := &WorkflowCallEvent{EventName: eventName, Event: event, Inputs: json.RawMessage(raw)}
+
+	return func(client *github.Client) error { return a.onWorkflowCall(client, callEvent) }, nil
+}
+
+// parseEvent parses the payload of an event:
+// the events unknown by go-github are handled by ghactions (schedule, classic projects, registry packages, repository advisories),
+// the others by github.ParseWebHook.
+func parseEvent(eventName string, payload []byte) (any, error) {
+	var event any
+
+	switch eventName {
+	case "schedule":
+		event = &ScheduleEvent{}
+	case "project":
+		event = &ClassicProjectEvent{}
+	case "project_card":
+		event = &ClassicProjectCardEvent{}
+	case "project_column":
+		event = &ClassicProjectColumnEvent{}
+	case "registry_package":
+		event = &RegistryPackageEvent{}
+	case "repository_advisory":
+		event = &RepositoryAdvisoryEvent{}
+	default:
+		if github.EventForType(eventName) == nil {
+			return nil, errUnknownEvent
+		}
+
+		return github.ParseWebHook(eventName, payload)
+	}
+
+	err := json.Unmarshal(payload, event)
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}